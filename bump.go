@@ -0,0 +1,730 @@
+package gobump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/builtin"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// Options is the options of updating the module paths with Bump.
+//
+// The callbacks are called from a single goroutine, in the order the files
+// are walked. Any of them can be nil.
+type Options struct {
+	// Dir is the directory of the module to update on the disk. The go
+	// commands are run in it, so they see the files on the disk rather than
+	// the ones in FS.
+	Dir string
+
+	// FS is the file system the files of the module are read from and
+	// written to. If nil, DirFS(Dir) is used.
+	//
+	// If FS is not backed by Dir, such as MemFS or Overlay, the go commands
+	// cannot see the updated files, so Bump fails instead of running them
+	// unless NoGoGet is set or there are no dependencies to update, and
+	// neither Verify nor VerifyTests is set. Dir can be empty then.
+	FS WriteFS
+
+	// NewPaths is a list of the new module paths, each of them referring to
+	// the module itself or one of its direct dependencies.
+	NewPaths []string
+
+	// Registry is the registry of the transformers updating the files. If
+	// nil, the built-in transformers with the default configuration are
+	// used.
+	Registry *transformers.Registry
+
+	// Workers is the number of files processed concurrently. If not
+	// positive, runtime.GOMAXPROCS(0) workers are used.
+	Workers int
+
+	// KeepGoing makes the files failed to be processed recorded instead of
+	// stopping the update. If any file fails, Bump returns FilesFailedError
+	// once the update is complete.
+	KeepGoing bool
+
+	// NoGoGet disables running 'go get' for the updated dependencies and
+	// 'go mod tidy' afterwards.
+	NoGoGet bool
+
+	// Offline makes the update done without accessing the network. Instead of
+	// running 'go get', the new versions of the dependencies are resolved from
+	// the vendor directory or the module cache reported by 'go env', the
+	// go.mod and go.sum files are updated accordingly, and the go commands
+	// are run with "-mod=mod" added to the configured GOFLAGS and with
+	// "GOPROXY=off". It has no effect with NoGoGet.
+	Offline bool
+
+	// Verify enables running 'go build' and 'go vet' in every updated module
+	// once the update is complete. If the modules fail to build, Bump
+	// returns VerifyError.
+	Verify bool
+
+	// VerifyTests enables also running 'go test' when verifying the update.
+	VerifyTests bool
+
+	// Runner runs the go commands. If nil, ExecRunner is used.
+	Runner Runner
+
+	// Env is a list of the environment variables in the "KEY=value" form,
+	// such as "GOFLAGS=-mod=mod" or "GOWORK=off", the go commands are run
+	// with in addition to the environment of the current process.
+	Env []string
+
+	// CommandTimeout limits the duration of every go command. If zero, the
+	// commands are only stopped once the context is done.
+	CommandTimeout time.Duration
+
+	// Logger receives the messages of the transformers. If it is nil, the
+	// messages are discarded.
+	Logger *log.Logger
+
+	// OnChange is called for every change made to a file.
+	OnChange func(transformers.Change)
+
+	// OnWarning is called for every problem found in a file that requires
+	// attention.
+	OnWarning func(transformers.Warning)
+
+	// OnSkipped is called for every file left unchanged.
+	OnSkipped func(SkippedFile)
+
+	// OnFailed is called for every file failed to be processed with
+	// KeepGoing.
+	OnFailed func(FailedFile)
+
+	// OnNote is called for every explanation of a non-trivial update.
+	OnNote func(string)
+
+	// OnCommandStart is called before running a command.
+	OnCommandStart func(args []string)
+
+	// OnCommand is called for every executed command.
+	OnCommand func(Command)
+
+	// OnRemaining is called for every other major version of an updated
+	// dependency still required after 'go mod tidy'.
+	OnRemaining func(RemainingModule)
+
+	// OnVerify is called with the report of building the updated modules.
+	OnVerify func(*VerifyReport)
+}
+
+// FilesFailedError is the error returned by Bump if some of the files failed
+// to be processed with Options.KeepGoing.
+type FilesFailedError struct {
+	Files []FailedFile
+}
+
+func (e *FilesFailedError) Error() string {
+	if len(e.Files) == 1 {
+		return "1 file failed to be processed"
+	}
+
+	return fmt.Sprintf("%d files failed to be processed", len(e.Files))
+}
+
+// VerifyError is the error returned by Bump if the updated modules failed to
+// build with Options.Verify.
+type VerifyError struct {
+	Report *VerifyReport
+}
+
+func (e *VerifyError) Error() string {
+	if len(e.Report.Errors) == 0 {
+		return fmt.Sprintf("verification failed, %d command(s) exited with errors", len(e.Report.Failed))
+	}
+
+	return fmt.Sprintf("verification failed with %d error(s)", len(e.Report.Errors))
+}
+
+// fileResult is the result of processing a single file.
+type fileResult struct {
+	changes  []transformers.Change
+	warnings []transformers.Warning
+	skipped  string
+	err      error
+}
+
+// Bump updates the module paths to the new ones in the module located in
+// Options.Dir. All the paths are updated in a single pass over the files,
+// followed by a single 'go get' with all the updated dependencies and a
+// single 'go mod tidy'.
+//
+// It returns the report of the update, which is complete even if the update
+// fails.
+func Bump(ctx context.Context, opts Options) (*Report, error) {
+	r := NewReport(opts.NewPaths...)
+
+	err := bump(ctx, opts, r)
+	r.Finish(err)
+
+	return r, err
+}
+
+func bump(ctx context.Context, opts Options, r *Report) error {
+	if len(opts.NewPaths) == 0 {
+		return errors.New("no module path given")
+	}
+
+	fsys := opts.FS
+	if fsys == nil {
+		fsys = DirFS(opts.Dir)
+	}
+
+	mf, err := readModFile(fsys, opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPaths(mf, opts.NewPaths); err != nil {
+		return err
+	}
+
+	if err := checkEnv(opts.Env); err != nil {
+		return err
+	}
+
+	for _, p := range opts.NewPaths {
+		if v := incompatibleVersion(mf, p); v != "" {
+			pfx := pathx.ModulePrefix(p)
+			note := fmt.Sprintf(
+				"%s is required at %s, a version published before the module adopted go.mod, "+
+					"so it has no major version suffix; the requirement is dropped in favour of %s "+
+					"and the imports of %s are rewritten to %s",
+				pfx, v, p, pfx, p,
+			)
+
+			r.AddNote(note)
+			if opts.OnNote != nil {
+				opts.OnNote(note)
+			}
+		}
+	}
+
+	var deps []string
+	if !opts.NoGoGet {
+		for _, p := range opts.NewPaths {
+			if shouldRunGoGet(mf, p) {
+				deps = append(deps, p)
+			}
+		}
+	}
+
+	if (len(deps) > 0 || opts.Verify || opts.VerifyTests) && !isDirFS(fsys, opts.Dir) {
+		return errors.New(
+			"cannot run the go commands as the files are not written to the module directory, " +
+				"set NoGoGet and leave Verify unset to update the files only",
+		)
+	}
+
+	var offline *offlineUpdate
+	if opts.Offline && (len(deps) > 0 || opts.Verify || opts.VerifyTests) {
+		cacheDir, env, err := offlineEnv(ctx, opts, r)
+		if err != nil {
+			return err
+		}
+		opts.Env = env
+
+		// The versions are resolved before any file is changed, so that the
+		// module is left intact if the cache lacks them.
+		if len(deps) > 0 {
+			if offline, err = resolveOffline(opts.Dir, cacheDir, deps); err != nil {
+				return err
+			}
+		}
+	}
+
+	modDirs, err := transformModule(ctx, opts, r, fsys)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case offline != nil:
+		if err := bumpOffline(ctx, opts, r, offline); err != nil {
+			return err
+		}
+	case len(deps) > 0:
+		args := []string{"go", "get"}
+		for _, d := range deps {
+			args = append(args, d+"@latest")
+		}
+
+		if _, err := runCommand(ctx, opts, r, opts.Dir, args...); err != nil {
+			return err
+		}
+
+		if _, err := runCommand(ctx, opts, r, opts.Dir, "go", "mod", "tidy"); err != nil {
+			return err
+		}
+	}
+
+	if len(deps) > 0 {
+		if err := checkRemaining(ctx, opts, r, deps, modDirs); err != nil {
+			return err
+		}
+	}
+
+	if opts.Verify || opts.VerifyTests {
+		if err := verify(ctx, opts, r, modDirs); err != nil {
+			return err
+		}
+	}
+
+	if ff := r.FailedFiles(); len(ff) > 0 {
+		return &FilesFailedError{Files: ff}
+	}
+
+	return nil
+}
+
+// bumpOffline updates the requirements of the dependencies to the versions
+// resolved locally in place of 'go get' and tidies the module.
+func bumpOffline(ctx context.Context, opts Options, r *Report, u *offlineUpdate) error {
+	dropped, err := requireOffline(opts.Dir, u.versions, func(c transformers.Change) {
+		r.AddChange(c)
+		if opts.OnChange != nil {
+			opts.OnChange(c)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := sumOffline(opts.Dir, u.cacheDir, dropped); err != nil {
+		return err
+	}
+
+	if _, err := runCommand(ctx, opts, r, opts.Dir, "go", "mod", "tidy"); err != nil {
+		return fmt.Errorf("%w; the module cache at %s may lack the modules required by the new versions", err, u.cacheDir)
+	}
+
+	return nil
+}
+
+// checkRemaining records the other major versions of the updated
+// dependencies still required by the other modules in the build lists of the
+// modules located in the given slash-separated directories relative to the
+// module directory.
+func checkRemaining(ctx context.Context, opts Options, r *Report, deps, moduleDirs []string) error {
+	var remaining []RemainingModule
+	for _, d := range moduleDirs {
+		dir := filepath.Join(opts.Dir, filepath.FromSlash(d))
+
+		graph, err := runInternalCommand(ctx, opts, r, dir, "go", "mod", "graph")
+		if err != nil {
+			return err
+		}
+
+		remaining = append(remaining, RemainingModules(graph, deps)...)
+	}
+
+	for _, m := range remaining {
+		r.AddRemaining(m)
+		if opts.OnRemaining != nil {
+			opts.OnRemaining(m)
+		}
+	}
+
+	for _, d := range deps {
+		found := false
+		for _, m := range remaining {
+			found = found || m.NewPath == d
+		}
+
+		if !found {
+			note := fmt.Sprintf("no other major version of %s is left in the build list", pathx.ModulePrefix(d))
+
+			r.AddNote(note)
+			if opts.OnNote != nil {
+				opts.OnNote(note)
+			}
+		}
+	}
+
+	return nil
+}
+
+// transformModule runs the transformers against the files of the module. It
+// returns the slash-separated directories of the modules found in the tree.
+func transformModule(ctx context.Context, opts Options, r *Report, fsys WriteFS) ([]string, error) {
+	reg := opts.Registry
+	if reg == nil {
+		reg = builtin.NewRegistry(builtin.Config{})
+	}
+
+	modules := NewModuleResolverFS(fsys)
+
+	// The tree is walked once. The files are listed first, as the modules of
+	// the tree must be known before any file is transformed, so that the
+	// imports of the nested modules are not attributed to the enclosing one.
+	var files []string
+	err := WalkDir(ctx, fsys, func(p string) error {
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	treeModules, err := modulePaths(fsys, files)
+	if err != nil {
+		return nil, err
+	}
+
+	var modDirs []string
+	err = forEachParallel(
+		ctx,
+		files,
+		opts.Workers,
+		func(ctx context.Context, p string) (fileResult, error) {
+			var res fileResult
+
+			regs := reg.LookupFile(fsys, p)
+			if len(regs) == 0 {
+				res.skipped = SkipNoTransformer
+				return res, nil
+			}
+
+			err := func() error {
+				m, err := modules.Owner(p)
+				if err != nil {
+					return err
+				}
+
+				ok, err := TransformFile(
+					fsys,
+					transformers.Context{
+						Path:    p,
+						Module:  m,
+						Modules: treeModules,
+						Logger:  opts.Logger,
+						OnChange: func(c transformers.Change) {
+							res.changes = append(res.changes, c)
+						},
+						OnWarning: func(w transformers.Warning) {
+							res.warnings = append(res.warnings, w)
+						},
+					},
+					opts.NewPaths,
+					regs...,
+				)
+				if err != nil {
+					return err
+				}
+
+				if !ok {
+					res.skipped = SkipNoChanges
+				}
+
+				return nil
+			}()
+			if err != nil {
+				if !opts.KeepGoing {
+					return res, fmt.Errorf("%s: %w", p, err)
+				}
+
+				// The file is left intact, so the changes made to it in
+				// memory are not reported.
+				return fileResult{err: err}, nil
+			}
+
+			return res, nil
+		},
+		func(p string, res fileResult) error {
+			if path.Base(p) == "go.mod" {
+				modDirs = append(modDirs, path.Dir(p))
+			}
+
+			for _, c := range res.changes {
+				r.AddChange(c)
+				if opts.OnChange != nil {
+					opts.OnChange(c)
+				}
+			}
+
+			for _, w := range res.warnings {
+				r.AddWarning(w)
+				if opts.OnWarning != nil {
+					opts.OnWarning(w)
+				}
+			}
+
+			if res.skipped != "" {
+				r.AddSkipped(p, res.skipped)
+				if opts.OnSkipped != nil {
+					opts.OnSkipped(SkippedFile{Path: p, Reason: res.skipped})
+				}
+			}
+
+			if res.err != nil {
+				r.AddFailed(p, res.err)
+				if opts.OnFailed != nil {
+					opts.OnFailed(FailedFile{Path: p, Error: res.err.Error()})
+				}
+			}
+
+			return nil
+		},
+	)
+
+	return modDirs, err
+}
+
+// verify builds the modules located in the given slash-separated directories
+// relative to the module directory and records the diagnostics reported by
+// the go command.
+func verify(ctx context.Context, opts Options, r *Report, moduleDirs []string) error {
+	commands := [][]string{
+		{"go", "build", "./..."},
+		{"go", "vet", "./..."},
+	}
+	if opts.VerifyTests {
+		commands = append(commands, []string{"go", "test", "./..."})
+	}
+
+	var (
+		all    []BuildError
+		failed []CommandFailure
+	)
+	for _, d := range moduleDirs {
+		dir := filepath.Join(opts.Dir, filepath.FromSlash(d))
+
+		for _, args := range commands {
+			o, err := runCommand(ctx, opts, r, dir, args...)
+
+			code, ok := exitCode(err)
+			if err != nil && !ok {
+				return err
+			}
+
+			ee := ParseBuildErrors(o)
+			ResolveBuildErrors(dir, ee)
+
+			for _, e := range ee {
+				e.File = path.Join(d, filepath.ToSlash(e.File))
+				all = append(all, e)
+			}
+
+			if code != 0 {
+				f := CommandFailure{
+					Dir:      d,
+					Args:     args,
+					ExitCode: code,
+				}
+
+				// The failures without diagnostics, such as the missing
+				// modules or the test panics, are explained by the output.
+				if len(ee) == 0 {
+					f.Output = o
+				}
+
+				failed = append(failed, f)
+			}
+		}
+	}
+
+	v := NewVerifyReport(dedupBuildErrors(all))
+	v.Failed = failed
+	r.SetVerify(v)
+	if opts.OnVerify != nil {
+		opts.OnVerify(v)
+	}
+
+	if len(v.Errors) > 0 || len(failed) > 0 {
+		return &VerifyError{Report: v}
+	}
+
+	return nil
+}
+
+// checkEnv checks that the environment variables are given as KEY=value.
+func checkEnv(env []string) error {
+	for _, e := range env {
+		if k, _, ok := strings.Cut(e, "="); !ok || k == "" {
+			return fmt.Errorf("invalid environment variable %q, want KEY=value", e)
+		}
+	}
+
+	return nil
+}
+
+// runCommand runs the command in the given directory and records it to the
+// report.
+//
+// It returns the combined output of the command.
+func runCommand(
+	ctx context.Context,
+	opts Options,
+	r *Report,
+	dir string,
+	args ...string,
+) (string, error) {
+	return execCommand(ctx, opts, r, dir, false, args...)
+}
+
+// runInternalCommand runs the command inspecting the module in the given
+// directory, such as 'go mod graph', and records it to the report as
+// internal.
+//
+// It returns the combined output of the command.
+func runInternalCommand(
+	ctx context.Context,
+	opts Options,
+	r *Report,
+	dir string,
+	args ...string,
+) (string, error) {
+	return execCommand(ctx, opts, r, dir, true, args...)
+}
+
+// execCommand runs the command for runCommand and runInternalCommand.
+func execCommand(
+	ctx context.Context,
+	opts Options,
+	r *Report,
+	dir string,
+	internal bool,
+	args ...string,
+) (string, error) {
+	if opts.OnCommandStart != nil {
+		opts.OnCommandStart(args)
+	}
+
+	runner := opts.Runner
+	if runner == nil {
+		runner = ExecRunner{}
+	}
+
+	cctx := ctx
+	if opts.CommandTimeout > 0 {
+		var cancel context.CancelFunc
+		cctx, cancel = context.WithTimeout(ctx, opts.CommandTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	res, err := runner.Run(cctx, dir, opts.Env, args...)
+
+	c := Command{
+		Args:     args,
+		Dir:      dir,
+		Env:      opts.Env,
+		Output:   string(res),
+		Duration: time.Since(start),
+		Internal: internal,
+	}
+
+	if err != nil && ctx.Err() == nil && errors.Is(cctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out after %s", opts.CommandTimeout)
+	}
+
+	if code, ok := exitCode(err); ok {
+		c.ExitCode = code
+	} else if err != nil {
+		c.ExitCode = -1
+	}
+
+	r.AddCommand(c)
+	if opts.OnCommand != nil {
+		opts.OnCommand(c)
+	}
+
+	if err != nil {
+		return c.Output, fmt.Errorf("error running '%s': %w", strings.Join(args, " "), err)
+	}
+
+	return c.Output, nil
+}
+
+// exitCode returns the exit code of the command failed with the given error.
+// It returns false if the command did not fail or could not be run.
+func exitCode(err error) (int, bool) {
+	var exitErr interface{ ExitCode() int }
+	if err == nil || !errors.As(err, &exitErr) {
+		return 0, false
+	}
+
+	return exitErr.ExitCode(), true
+}
+
+// CheckPaths checks that the new module paths can be applied to the module
+// located in moduleDir, the same way Bump does before changing any file. It
+// allows the callers to validate the paths before preparing the update.
+func CheckPaths(moduleDir string, newPaths []string) error {
+	if len(newPaths) == 0 {
+		return errors.New("no module path given")
+	}
+
+	mf, err := readModFile(os.DirFS(moduleDir), moduleDir)
+	if err != nil {
+		return err
+	}
+
+	return checkPaths(mf, newPaths)
+}
+
+// checkPaths checks that every path is valid, refers to the module of the
+// go.mod file or one of its direct dependencies, and that no two paths refer
+// to the same module.
+func checkPaths(mf *modfile.File, paths []string) error {
+	mp, dr := mf.Module.Mod.Path, directRequires(mf)
+
+	seen := map[string]string{}
+	for _, p := range paths {
+		if err := checkPath(mp, dr, p); err != nil {
+			return err
+		}
+
+		pfx := pathx.ModulePrefix(p)
+		if prev, ok := seen[pfx]; ok {
+			return fmt.Errorf("module paths '%s' and '%s' refer to the same module", prev, p)
+		}
+		seen[pfx] = p
+	}
+
+	return nil
+}
+
+func checkPath(modulePath string, requires []string, p string) error {
+	if err := module.CheckPath(p); err != nil {
+		return fmt.Errorf("invalid module path %q: %w", p, err)
+	}
+
+	po := pathx.ModulePrefix(p)
+	if pathx.ModulePrefix(modulePath) == po {
+		return nil
+	}
+
+	for _, m := range requires {
+		if pathx.ModulePrefix(m) == po {
+			return nil
+		}
+	}
+
+	return fmt.Errorf(
+		"module path '%s' does not match module '%s' or any of its direct dependencies",
+		p,
+		modulePath,
+	)
+}
+
+// shouldRunGoGet reports if 'go get' is to be run for the new module path,
+// which is the case for the dependencies but not for the module itself.
+func shouldRunGoGet(mf *modfile.File, p string) bool {
+	// The module path is assumed to be a direct dependency of the module.
+	return pathx.ModulePrefix(mf.Module.Mod.Path) != pathx.ModulePrefix(p)
+}
@@ -0,0 +1,99 @@
+package gobump_test
+
+import (
+	"context"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+// syntheticTree generates a module of n packages with a single .go file each,
+// every matchEvery-th of which imports example.org/foo/bar. If matchEvery is
+// 0, none of the files import it.
+func syntheticTree(n, matchEvery int) map[string][]byte {
+	files := map[string][]byte{
+		"go.mod": []byte("module example.org/app\n\ngo 1.20\n\nrequire example.org/foo/bar v1.0.0\n"),
+	}
+
+	for i := 0; i < n; i++ {
+		var b strings.Builder
+
+		fmt.Fprintf(&b, "package pkg%d\n\nimport (\n\t\"fmt\"\n\t\"strings\"\n", i)
+		if matchEvery > 0 && i%matchEvery == 0 {
+			b.WriteString("\n\t\"example.org/foo/bar/sub\"\n")
+		}
+		b.WriteString(")\n\n")
+
+		for j := 0; j < 20; j++ {
+			fmt.Fprintf(
+				&b,
+				"// Func%d does things.\nfunc Func%d(s string) string {\n\tif s == \"\" {\n\t\treturn fmt.Sprint(%d)\n\t}\n\treturn strings.ToUpper(s)\n}\n\n",
+				j, j, j,
+			)
+		}
+
+		if matchEvery > 0 && i%matchEvery == 0 {
+			b.WriteString("var _ = sub.Value\n")
+		}
+
+		files[fmt.Sprintf("pkg%d/pkg%d.go", i, i)] = []byte(b.String())
+	}
+
+	return files
+}
+
+func BenchmarkBump(b *testing.B) {
+	for _, bm := range []struct {
+		name       string
+		matchEvery int
+	}{
+		{name: "no matching files", matchEvery: 0},
+		{name: "1% matching files", matchEvery: 100},
+		{name: "10% matching files", matchEvery: 10},
+		{name: "all matching files", matchEvery: 1},
+	} {
+		files := syntheticTree(1000, bm.matchEvery)
+
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				fsys := NewMemFS(files)
+				b.StartTimer()
+
+				// A single worker keeps the cost comparable to the baseline.
+				if _, err := Bump(context.Background(), Options{
+					FS:       fsys,
+					NewPaths: []string{"example.org/foo/bar/v2"},
+					Workers:  1,
+					NoGoGet:  true,
+				}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+
+	// The cost of parsing every file in full, which is the lower bound of the
+	// cost of the tree walk without the prefilter.
+	b.Run("baseline full parse", func(b *testing.B) {
+		files := syntheticTree(1000, 100)
+
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for p, f := range files {
+				if !strings.HasSuffix(p, ".go") {
+					continue
+				}
+
+				if _, err := parser.ParseFile(token.NewFileSet(), "", f, parser.ParseComments); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,497 @@
+package gobump_test
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// writeModule writes the files of a module into a temporary directory.
+func writeModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+func TestBump(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":      "module example.org/foo\n\ngo 1.20\n",
+		"main.go":     "package main\n\nimport _ \"example.org/foo/pkg\"\n",
+		"pkg/pkg.go":  "package pkg\n",
+		"README.txt":  "example.org/foo\n",
+		"sub/go.mod":  "module example.org/foo/sub\n\ngo 1.20\n",
+		"sub/sub.go":  "package sub\n\nimport _ \"example.org/foo/pkg\"\n",
+		"sub/main.go": "package main\n\nimport _ \"example.org/foo/sub/x\"\n",
+	})
+
+	var changes []transformers.Change
+	r, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/foo/v2"},
+		NoGoGet:  true,
+		OnChange: func(c transformers.Change) { changes = append(changes, c) },
+	})
+	if err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	want := []string{"go.mod", "main.go", "sub/sub.go"}
+	if got := r.ChangedFiles(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Bump() changed files = %v, want %v", got, want)
+	}
+
+	if len(changes) != 3 {
+		t.Fatalf("Bump() reported %d changes, want 3", len(changes))
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nimport _ \"example.org/foo/v2/pkg\"\n"; string(bb) != want {
+		t.Fatalf("Bump() wrote %q, want %q", bb, want)
+	}
+}
+
+func TestBump_overlay(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n",
+		"main.go": "package main\n\nimport _ \"example.org/foo/pkg\"\n",
+	})
+
+	o := NewOverlay(os.DirFS(dir))
+	if _, err := Bump(context.Background(), Options{
+		FS:       o,
+		NewPaths: []string{"example.org/foo/v2"},
+		NoGoGet:  true,
+	}); err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	if got := string(o.Edits()["main.go"]); got != "package main\n\nimport _ \"example.org/foo/v2/pkg\"\n" {
+		t.Fatalf("Bump() wrote %q to the overlay", got)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != "package main\n\nimport _ \"example.org/foo/pkg\"\n" {
+		t.Fatalf("Bump() modified the file on the disk: %q", bb)
+	}
+}
+
+func TestBump_memFS(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"go.mod":  []byte("module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n"),
+		"main.go": []byte("package main\n\nimport _ \"example.org/bar/pkg\"\n"),
+	})
+
+	if _, err := Bump(context.Background(), Options{
+		FS:       fsys,
+		NewPaths: []string{"example.org/bar/v2"},
+		NoGoGet:  true,
+	}); err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	bb, err := fs.ReadFile(fsys, "main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nimport _ \"example.org/bar/v2/pkg\"\n"; string(bb) != want {
+		t.Fatalf("Bump() wrote %q, want %q", bb, want)
+	}
+}
+
+func TestBump_goCommandsWithoutDir(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{
+			name: "should reject go get",
+			opts: Options{NewPaths: []string{"example.org/bar/v2"}},
+		},
+		{
+			name: "should reject verification",
+			opts: Options{NewPaths: []string{"example.org/foo/v2"}, NoGoGet: true, Verify: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &FakeRunner{}
+
+			o := NewOverlay(os.DirFS(dir))
+			tt.opts.Dir = dir
+			tt.opts.FS = o
+			tt.opts.Runner = runner
+
+			if _, err := Bump(context.Background(), tt.opts); err == nil {
+				t.Fatal("Bump() error = nil, want error")
+			}
+
+			if n := len(runner.Runs()); n != 0 {
+				t.Fatalf("Bump() ran %d commands, want none", n)
+			}
+
+			if n := len(o.Edits()); n != 0 {
+				t.Fatalf("Bump() changed %d files, want none", n)
+			}
+		})
+	}
+}
+
+func TestBump_keepGoing(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":      "module example.org/foo\n\ngo 1.20\n",
+		"a.go":        "package {{ .Package }}\n\nimport _ \"example.org/foo/pkg\"\n",
+		"b.go":        "package main\n\nimport _ \"example.org/foo/pkg\"\n",
+		"pkg/pkg.go":  "package pkg\n",
+		"pkg/pkg2.go": "package pkg\n",
+	})
+
+	opts := Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/foo/v2"},
+		NoGoGet:  true,
+	}
+
+	if _, err := Bump(context.Background(), opts); err == nil {
+		t.Fatal("Bump() error = nil, want error")
+	}
+
+	opts.KeepGoing = true
+	opts.NewPaths = []string{"example.org/foo/v3"}
+
+	r, err := Bump(context.Background(), opts)
+
+	var failed *FilesFailedError
+	if !errors.As(err, &failed) {
+		t.Fatalf("Bump() error = %v, want FilesFailedError", err)
+	}
+
+	if len(failed.Files) != 1 || failed.Files[0].Path != "a.go" {
+		t.Fatalf("Bump() failed files = %+v, want a.go", failed.Files)
+	}
+
+	if want := []string{"b.go", "go.mod"}; !reflect.DeepEqual(r.ChangedFiles(), want) {
+		t.Fatalf("Bump() changed files = %v, want %v", r.ChangedFiles(), want)
+	}
+}
+
+func TestBump_invalidPath(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.org/foo\n\ngo 1.20\n",
+	})
+
+	tests := []struct {
+		name     string
+		newPaths []string
+	}{
+		{
+			name: "should return error if no path is given",
+		},
+		{
+			name:     "should return error if the path is invalid",
+			newPaths: []string{"example.org/foo/v1"},
+		},
+		{
+			name:     "should return error if the path refers to an unknown module",
+			newPaths: []string{"example.org/bar/v2"},
+		},
+		{
+			name:     "should return error if the paths refer to the same module",
+			newPaths: []string{"example.org/foo/v2", "example.org/foo/v3"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Bump(context.Background(), Options{
+				Dir:      dir,
+				NewPaths: tt.newPaths,
+				NoGoGet:  true,
+			})
+			if err == nil {
+				t.Fatal("Bump() error = nil, want error")
+			}
+
+			if r.Error != err.Error() {
+				t.Fatalf("Bump() report error = %q, want %q", r.Error, err)
+			}
+
+			if cerr := CheckPaths(dir, tt.newPaths); cerr == nil || cerr.Error() != err.Error() {
+				t.Fatalf("CheckPaths() error = %v, want %v", cerr, err)
+			}
+		})
+	}
+}
+
+func TestBump_runner(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	runner := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go mod tidy": {Output: "go: example.org/bar/v2 not found\n", ExitCode: 1},
+		},
+	}
+
+	r, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   runner,
+		Env:      []string{"GOWORK=off", "GOPROXY=off"},
+	})
+	if err == nil {
+		t.Fatal("Bump() error = nil, want error")
+	}
+
+	want := []FakeRun{
+		{
+			Dir:  dir,
+			Env:  []string{"GOWORK=off", "GOPROXY=off"},
+			Args: []string{"go", "get", "example.org/bar/v2@latest"},
+		},
+		{
+			Dir:  dir,
+			Env:  []string{"GOWORK=off", "GOPROXY=off"},
+			Args: []string{"go", "mod", "tidy"},
+		},
+	}
+	if got := runner.Runs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Bump() ran %+v, want %+v", got, want)
+	}
+
+	if len(r.Commands) != 2 {
+		t.Fatalf("Bump() reported %d commands, want 2", len(r.Commands))
+	}
+
+	if c := r.Commands[1]; c.ExitCode != 1 || c.Output != "go: example.org/bar/v2 not found\n" {
+		t.Fatalf("Bump() reported command %+v, want exit code 1 and the output", c)
+	}
+}
+
+// blockingRunner is a runner whose commands run until the context is done.
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestBump_commandTimeout(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+	})
+
+	r, err := Bump(context.Background(), Options{
+		Dir:            dir,
+		NewPaths:       []string{"example.org/bar/v2"},
+		Runner:         blockingRunner{},
+		CommandTimeout: time.Millisecond,
+	})
+	if err == nil || !strings.Contains(err.Error(), "timed out after 1ms") {
+		t.Fatalf("Bump() error = %v, want timeout error", err)
+	}
+
+	if len(r.Commands) != 1 || r.Commands[0].ExitCode != -1 {
+		t.Fatalf("Bump() reported commands %+v, want one with exit code -1", r.Commands)
+	}
+}
+
+func TestBump_invalidEnv(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod": "module example.org/foo\n\ngo 1.20\n",
+	})
+
+	runner := &FakeRunner{}
+	if _, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/foo/v2"},
+		Runner:   runner,
+		Env:      []string{"GOWORK"},
+	}); err == nil {
+		t.Fatal("Bump() error = nil, want error")
+	}
+
+	if n := len(runner.Runs()); n != 0 {
+		t.Fatalf("Bump() ran %d commands, want none", n)
+	}
+}
+
+func TestBump_remaining(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	runner := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go mod graph": {
+				Output: "example.org/foo example.org/bar/v2@v2.0.0\n" +
+					"example.org/foo example.org/baz@v1.0.0\n" +
+					"example.org/baz@v1.0.0 example.org/bar@v1.0.0\n",
+			},
+		},
+	}
+
+	var remaining []RemainingModule
+	r, err := Bump(context.Background(), Options{
+		Dir:         dir,
+		NewPaths:    []string{"example.org/bar/v2"},
+		Runner:      runner,
+		OnRemaining: func(m RemainingModule) { remaining = append(remaining, m) },
+	})
+	if err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	want := []RemainingModule{
+		{
+			Path:    "example.org/bar",
+			Version: "v1.0.0",
+			NewPath: "example.org/bar/v2",
+			Chains: [][]string{
+				{"example.org/foo", "example.org/baz@v1.0.0", "example.org/bar@v1.0.0"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(remaining, want) {
+		t.Fatalf("Bump() reported remaining modules %+v, want %+v", remaining, want)
+	}
+	if !reflect.DeepEqual(r.Remaining, want) {
+		t.Fatalf("Bump() recorded remaining modules %+v, want %+v", r.Remaining, want)
+	}
+
+	for _, c := range r.Commands {
+		if got, want := c.Internal, strings.Join(c.Args, " ") == "go mod graph"; got != want {
+			t.Fatalf("Bump() recorded command %v with Internal = %v, want %v", c.Args, got, want)
+		}
+	}
+}
+
+// graphRunner is a runner returning the module graphs of the modules located
+// in the given directories.
+type graphRunner map[string]string
+
+func (g graphRunner) Run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	if strings.Join(args, " ") != "go mod graph" {
+		return nil, nil
+	}
+
+	return []byte(g[dir]), nil
+}
+
+func TestBump_remainingNested(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":        "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go":       "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+		"tools/go.mod":  "module example.org/foo/tools\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"tools/main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	runner := graphRunner{
+		dir: "example.org/foo example.org/bar/v2@v2.0.0\n",
+		filepath.Join(dir, "tools"): "example.org/foo/tools example.org/bar/v2@v2.0.0\n" +
+			"example.org/foo/tools example.org/baz@v1.0.0\n" +
+			"example.org/baz@v1.0.0 example.org/bar@v1.0.0\n",
+	}
+
+	r, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   runner,
+	})
+	if err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	want := []RemainingModule{
+		{
+			Path:    "example.org/bar",
+			Version: "v1.0.0",
+			NewPath: "example.org/bar/v2",
+			Chains: [][]string{
+				{"example.org/foo/tools", "example.org/baz@v1.0.0", "example.org/bar@v1.0.0"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(r.Remaining, want) {
+		t.Fatalf("Bump() recorded remaining modules %+v, want %+v", r.Remaining, want)
+	}
+
+	if len(r.Notes) != 0 {
+		t.Fatalf("Bump() recorded notes %q, want none", r.Notes)
+	}
+}
+
+func TestBump_verifyFailure(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n",
+		"main.go": "package main\n\nimport _ \"example.org/foo/pkg\"\n",
+	})
+
+	output := "go: updates to go.mod needed; to update it:\n\tgo mod tidy\n"
+	runner := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go build ./...": {Output: output, ExitCode: 1},
+		},
+	}
+
+	_, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/foo/v2"},
+		NoGoGet:  true,
+		Verify:   true,
+		Runner:   runner,
+	})
+
+	var verifyErr *VerifyError
+	if !errors.As(err, &verifyErr) {
+		t.Fatalf("Bump() error = %v, want VerifyError", err)
+	}
+
+	want := []CommandFailure{
+		{
+			Dir:      ".",
+			Args:     []string{"go", "build", "./..."},
+			ExitCode: 1,
+			Output:   output,
+		},
+	}
+	if got := verifyErr.Report.Failed; !reflect.DeepEqual(got, want) {
+		t.Fatalf("Bump() failed commands = %+v, want %+v", got, want)
+	}
+
+	if n := len(verifyErr.Report.Errors); n != 0 {
+		t.Fatalf("Bump() reported %d diagnostics, want none", n)
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
+)
+
+// listFlag is a flag that can be given several times.
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *listFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// runDeprecate marks the module located in wd as deprecated in favour of the
+// new module path given in args and retracts the given versions.
+func runDeprecate(ctx context.Context, wd string, jsonOut bool, args []string) error {
+	var (
+		retract listFlag
+		reason  string
+	)
+
+	fs := flag.NewFlagSet("deprecate", flag.ContinueOnError)
+	fs.BoolVar(&jsonOut, "json", jsonOut, "print a report in JSON format instead of the progress")
+	fs.Var(&retract, "retract", "retract a version, such as 'v1.0.0', or a version interval, such as '[v1.0.0, v1.2.0]'; can be repeated")
+	fs.StringVar(&reason, "retract-reason", "", "rationale of the retractions written above the retract directives")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: gobump deprecate [-retract <versions>]... [-retract-reason <text>] <new go module path>")
+	}
+	newPath := fs.Arg(0)
+
+	var rr []gomodfile.Retraction
+	for _, v := range retract {
+		r, err := gomodfile.ParseRetraction(v)
+		if err != nil {
+			return err
+		}
+		r.Rationale = reason
+		rr = append(rr, r)
+	}
+
+	out := &output{
+		json:   jsonOut,
+		report: gobump.NewReport(newPath),
+	}
+
+	reg := transformers.Registration{
+		Name: "gomod-deprecate",
+		New: func([]string) transformers.FileTransformer {
+			return gomodfile.Deprecate(newPath, rr...)
+		},
+	}
+
+	ok, err := gobump.TransformFile(
+		gobump.DirFS(wd),
+		transformers.Context{
+			Path:   "go.mod",
+			Logger: log.New(os.Stderr, "", 0),
+			OnChange: func(c transformers.Change) {
+				out.report.AddChange(c)
+				out.Change(c)
+			},
+		},
+		[]string{newPath},
+		reg,
+	)
+	if err == nil && !ok {
+		out.report.AddSkipped("go.mod", gobump.SkipNoChanges)
+	}
+
+	return out.Finish(err)
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/internal/git"
+)
+
+// gitBranch is the branch created for the update.
+type gitBranch struct {
+	repo *git.Repo
+
+	// name is the name of the branch.
+	name string
+
+	// orig is the branch or the commit checked out before the update.
+	orig string
+
+	// dirty is true if the working tree had uncommitted changes before the
+	// update.
+	dirty bool
+}
+
+// prepareGit checks that the git repository the directory belongs to has no
+// uncommitted changes, unless force is true, and switches to a new branch
+// named after the update.
+func prepareGit(
+	ctx context.Context,
+	wd string,
+	newPaths []string,
+	force bool,
+	out *output,
+) (*gitBranch, error) {
+	repo, err := git.Open(ctx, wd)
+	if err != nil {
+		return nil, err
+	}
+
+	dirty, err := repo.IsDirty(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if dirty && !force {
+		return nil, errors.New(
+			"the working tree has uncommitted changes, commit or stash them, or use -force",
+		)
+	}
+
+	orig, err := repo.CurrentBranch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The detached HEAD is restored by its commit.
+	if orig == "HEAD" {
+		if orig, err = repo.Head(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	b := &gitBranch{
+		repo:  repo,
+		name:  gobump.BranchName(newPaths),
+		orig:  orig,
+		dirty: dirty,
+	}
+
+	if err := repo.CreateBranch(ctx, b.name); err != nil {
+		return nil, err
+	}
+
+	out.Printf("created branch '%s'\n", b.name)
+
+	return b, nil
+}
+
+// abandonGit switches back to the branch checked out before the update and
+// deletes the branch created for it, as nothing is committed to it.
+//
+// The changes made to the working tree are discarded, unless it had
+// uncommitted changes before the update, in which case they are left as is.
+func abandonGit(ctx context.Context, b *gitBranch, out *output) error {
+	if b.dirty {
+		out.Printf("the working tree had uncommitted changes, the changes of the update are left in it\n")
+	} else if err := b.repo.Discard(ctx); err != nil {
+		return err
+	}
+
+	if err := b.repo.Checkout(ctx, b.orig); err != nil {
+		return err
+	}
+
+	if err := b.repo.DeleteBranch(ctx, b.name); err != nil {
+		return err
+	}
+
+	out.Printf("switched back to '%s' and deleted branch '%s'\n", b.orig, b.name)
+
+	return nil
+}
+
+// commitBump commits the update of the module located in dir. If all is
+// true, all the files in dir are committed, otherwise only the rewritten
+// files and the go.mod and go.sum files updated by the go command are.
+func commitBump(
+	ctx context.Context,
+	repo *git.Repo,
+	dir string,
+	all bool,
+	out *output,
+) error {
+	paths := []string{"."}
+	if !all {
+		paths = out.report.ChangedFiles()
+		if len(paths) == 0 && len(out.report.ExecutedCommands()) == 0 {
+			out.Printf("nothing to commit\n")
+			return nil
+		}
+
+		for _, f := range []string{"go.mod", "go.sum"} {
+			if _, err := os.Stat(filepath.Join(dir, f)); err == nil && !contains(paths, f) {
+				paths = append(paths, f)
+			}
+		}
+	}
+
+	versions, err := gobump.ParseRequiredVersions(dir)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.Commit(
+		ctx,
+		dir,
+		gobump.CommitMessage(out.report, versions),
+		paths...,
+	); err != nil {
+		return fmt.Errorf("cannot commit the update: %w", err)
+	}
+
+	out.Printf("committed the update\n")
+
+	return nil
+}
+
+// contains reports if the list contains the string.
+func contains(list []string, s string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+
+	return false
+}
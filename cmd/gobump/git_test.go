@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danilvpetrov/gobump"
+)
+
+func TestAbandonGit(t *testing.T) {
+	for _, name := range []string{"git", "go"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s is not installed", name)
+		}
+	}
+
+	wd := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module example.org/app\n\ngo 1.20\n\nrequire example.org/foo v1.0.0\n",
+		"main.go": "package main\n\nimport _ \"example.org/foo\"\n\nfunc main() {}\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(wd, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	git := func(args ...string) string {
+		t.Helper()
+
+		cmd := exec.Command("git", args...)
+		cmd.Dir = wd
+
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+
+		return strings.TrimSpace(string(out))
+	}
+	git("init", "-q", "-b", "main")
+	git("config", "user.name", "test")
+	git("config", "user.email", "test@example.org")
+	git("add", "-A")
+	git("commit", "-q", "-m", "initial")
+
+	ctx := context.Background()
+	newPaths := []string{"example.org/foo/v2"}
+	out := &output{json: true, report: gobump.NewReport(newPaths...)}
+
+	b, err := prepareGit(ctx, wd, newPaths, false, out)
+	if err != nil {
+		t.Fatalf("prepareGit() error = %v", err)
+	}
+
+	// The new module path cannot be fetched, so the update fails after the
+	// files are rewritten.
+	_, err = gobump.Bump(ctx, gobump.Options{
+		Dir:      wd,
+		NewPaths: newPaths,
+		Env:      []string{"GOPROXY=off", "GOFLAGS=-mod=mod", "GOWORK=off"},
+	})
+	if err == nil {
+		t.Fatal("Bump() error = nil, want an error")
+	}
+
+	if got := git("status", "--porcelain"); got == "" {
+		t.Fatal("Bump() left the working tree clean, want it modified")
+	}
+
+	if err := abandonGit(ctx, b, out); err != nil {
+		t.Fatalf("abandonGit() error = %v", err)
+	}
+
+	if got := git("rev-parse", "--abbrev-ref", "HEAD"); got != "main" {
+		t.Fatalf("abandonGit() left %q checked out, want main", got)
+	}
+
+	if got := git("branch", "--format=%(refname:short)"); got != "main" {
+		t.Fatalf("abandonGit() left branches %q, want main", got)
+	}
+
+	if got := git("status", "--porcelain"); got != "" {
+		t.Fatalf("abandonGit() left status %q, want clean", got)
+	}
+}
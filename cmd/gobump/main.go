@@ -1,13 +1,27 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+
+	"github.com/danilvpetrov/gobump"
+)
+
+// The exit codes of the tool. The invalid flags are reported with the exit
+// code 2 by the flag package.
+const (
+	exitError       = 1
+	exitFilesFailed = 3
 )
 
 func main() {
 	if err := run(); err != nil {
-		fmt.Printf("error: %s\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+
+		if errors.As(err, new(*gobump.FilesFailedError)) {
+			os.Exit(exitFilesFailed)
+		}
+		os.Exit(exitError)
 	}
 }
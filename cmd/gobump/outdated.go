@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danilvpetrov/gobump"
+)
+
+// runOutdated prints the direct dependencies of the module located in
+// opts.Dir that have published higher major versions. The go command asked
+// for the proxy configuration is run with the given options, and its output
+// is printed to stderr if it fails.
+func runOutdated(ctx context.Context, opts gobump.Options, jsonOut bool, args []string) error {
+	fs := flag.NewFlagSet("outdated", flag.ContinueOnError)
+	fs.BoolVar(&jsonOut, "json", jsonOut, "print the result in JSON format")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	opts.OnCommand = func(c gobump.Command) {
+		if c.ExitCode != 0 {
+			os.Stderr.WriteString(c.Output)
+		}
+	}
+
+	c, err := gobump.ReadProxyConfig(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	p, err := gobump.NewProxy(c, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := gobump.Outdated(ctx, p, opts.Dir)
+	if err != nil {
+		return err
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(res)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "MODULE\tCURRENT\tAVAILABLE\n")
+	for _, m := range res {
+		var available []string
+		for _, a := range m.Available {
+			available = append(available, fmt.Sprintf("%s (%s)", a.Path, a.Latest))
+		}
+
+		switch {
+		case m.Error != "":
+			available = append(available, "error: "+m.Error)
+		case m.Private:
+			available = append(available, "private, not checked")
+		case len(available) == 0:
+			available = append(available, "-")
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\n", m.Path, m.Major, strings.Join(available, ", "))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// output prints the results of the run. In text mode the callbacks print the
+// results as they come, and Finish prints the summary of the report. In JSON
+// mode only the report is printed by Finish.
+type output struct {
+	json bool
+
+	// report is the report of the run, which is replaced with the report
+	// returned by gobump.Bump once the update runs.
+	report *gobump.Report
+}
+
+// Printf prints a progress message in text mode.
+func (o *output) Printf(format string, args ...any) {
+	if !o.json {
+		fmt.Printf(format, args...)
+	}
+}
+
+// Change prints a change made to a file.
+func (o *output) Change(c transformers.Change) {
+	if c.New == "" {
+		o.Printf("%s:%d:%d: removed %s\n", c.Path, c.Line, c.Column, c.Old)
+	} else {
+		o.Printf("%s:%d:%d: %s -> %s\n", c.Path, c.Line, c.Column, c.Old, c.New)
+	}
+}
+
+// Note prints an explanation of a non-trivial update.
+func (o *output) Note(note string) {
+	o.Printf("note: %s\n", note)
+}
+
+// Warning prints a problem found in a file to stderr in text mode.
+func (o *output) Warning(w transformers.Warning) {
+	if !o.json {
+		fmt.Fprintf(os.Stderr, "warning: %s: %s\n", w.Path, w.Message)
+	}
+}
+
+// Failed prints the error of a file that failed to be processed to stderr in
+// text mode.
+func (o *output) Failed(f gobump.FailedFile) {
+	if !o.json {
+		fmt.Fprintf(os.Stderr, "error: %s: %s\n", f.Path, f.Error)
+	}
+}
+
+// CommandStart prints the command about to run in text mode.
+func (o *output) CommandStart(args []string) {
+	o.Printf("running '%s'...\n", strings.Join(args, " "))
+}
+
+// Command prints the output of an executed command to stderr in text mode,
+// except for the successful internal commands, such as 'go mod graph', whose
+// output is summarised instead.
+func (o *output) Command(c gobump.Command) {
+	if o.json {
+		return
+	}
+
+	if c.ExitCode == 0 && c.Internal {
+		return
+	}
+	os.Stderr.WriteString(c.Output)
+}
+
+// Remaining prints the chains of the requirements pulling in another major
+// version of an updated dependency still required after the update in text
+// mode.
+func (o *output) Remaining(m gobump.RemainingModule) {
+	if o.json {
+		return
+	}
+
+	fmt.Printf("\n%s %s is still required along with %s:\n", m.Path, m.Version, m.NewPath)
+	for _, c := range m.Chains {
+		fmt.Printf("  %s\n", strings.Join(c, " -> "))
+	}
+}
+
+// Verify prints the report of building the updated modules in text mode: the
+// diagnostics grouped by the symbol and by the file, followed by the failed
+// commands.
+func (o *output) Verify(v *gobump.VerifyReport) {
+	if o.json {
+		return
+	}
+
+	if len(v.Errors) > 0 {
+		printBuildErrors(v)
+	}
+
+	if len(v.Failed) > 0 {
+		fmt.Printf("\nfailed commands:\n")
+		for _, f := range v.Failed {
+			fmt.Printf("  %s: '%s' exited with status %d\n", f.Dir, strings.Join(f.Args, " "), f.ExitCode)
+		}
+	}
+}
+
+// printBuildErrors prints the diagnostics grouped by the symbol and by the
+// file.
+func printBuildErrors(v *gobump.VerifyReport) {
+	fmt.Printf("\nbuild errors by dependency symbol:\n")
+	for _, k := range sortedKeys(v.BySymbol) {
+		fmt.Printf("  %s\n", k)
+		for _, e := range v.BySymbol[k] {
+			fmt.Printf("    %s:%d:%d: %s\n", e.File, e.Line, e.Column, e.Message)
+		}
+	}
+
+	fmt.Printf("\nbuild errors by file:\n")
+	for _, k := range sortedKeys(v.ByFile) {
+		fmt.Printf("  %s\n", k)
+		for _, e := range v.ByFile[k] {
+			fmt.Printf("    %d:%d: %s\n", e.Line, e.Column, e.Message)
+		}
+	}
+}
+
+// sortedKeys returns the sorted keys of the map.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Finish completes the report. In JSON mode the report is printed to stdout.
+func (o *output) Finish(err error) error {
+	o.report.Finish(err)
+
+	if o.json {
+		if werr := o.report.WriteJSON(os.Stdout); werr != nil && err == nil {
+			return werr
+		}
+		return err
+	}
+
+	var failed *gobump.FilesFailedError
+	switch {
+	case errors.As(err, &failed):
+		fmt.Fprintf(os.Stderr, "\nfailed files:\n")
+		for _, f := range failed.Files {
+			fmt.Fprintf(os.Stderr, "  %s\n", f.Path)
+		}
+	case err != nil:
+	case len(o.report.ChangedFiles()) == 0:
+		fmt.Println("nothing to do")
+	default:
+		fmt.Println("done")
+	}
+
+	return err
+}
@@ -1,18 +1,20 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"log"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/danilvpetrov/gobump"
-	"github.com/danilvpetrov/gobump/transformers/gofile"
-	"github.com/danilvpetrov/gobump/transformers/gomodfile"
-	"github.com/danilvpetrov/gobump/transformers/protofile"
-	"golang.org/x/mod/module"
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/builtin"
 )
 
 func run() error {
@@ -21,132 +23,280 @@ func run() error {
 		return fmt.Errorf("cannot determine current directory: %s", err)
 	}
 
-	var noGoGet bool
+	var (
+		noGoGet    bool
+		offline    bool
+		doVerify   bool
+		withTests  bool
+		jsonOut    bool
+		workers    int
+		mdSkipList string
+		listFile   string
+		strategy   string
+		useGit     bool
+		force      bool
+		keepGoing  bool
+		chdir      string
+		env        listFlag
+		timeout    time.Duration
+	)
+	flag.StringVar(&chdir, "C", "", "change to the directory before running, the other paths are relative to it")
 	flag.BoolVar(&noGoGet, "n", false, "don't run 'go get' for the new module path")
+	flag.BoolVar(&offline, "offline", false, "update the requirements from the module cache or the vendor directory instead of running 'go get', without network access")
+	flag.BoolVar(&doVerify, "verify", false, "run 'go build' and 'go vet' after the update and report the breakages")
+	flag.BoolVar(&withTests, "verify-tests", false, "also run 'go test' when verifying the update")
+	flag.BoolVar(&jsonOut, "json", false, "print a report in JSON format instead of the progress")
+	flag.StringVar(&listFile, "f", "", "read the new module paths from the file, one per line")
+	flag.StringVar(
+		&strategy,
+		"strategy",
+		strategyPath,
+		"major version strategy of the module itself: 'path' updates the module in place, 'subdir' copies it into the vN subdirectory",
+	)
+	flag.BoolVar(&useGit, "git", false, "create a branch for the update and commit the changes")
+	flag.BoolVar(&force, "force", false, "run even if the git working tree has uncommitted changes")
+	flag.BoolVar(&keepGoing, "keep-going", false, "keep processing the other files if a file fails, and report the failed files at the end")
+	flag.Var(&env, "env", "set an environment variable for the go commands, such as 'GOWORK=off'; can be repeated")
+	flag.DurationVar(&timeout, "timeout", 0, "stop a go command if it runs longer than the duration, such as '5m'")
+	flag.IntVar(&workers, "j", 0, "number of files processed concurrently (defaults to the number of CPUs)")
+	flag.StringVar(
+		&mdSkipList,
+		"md-skip",
+		"Changelog",
+		"comma-separated list of Markdown section headings to leave intact",
+	)
 	flag.Usage = usage
 	flag.Parse()
 
-	newPath := flag.Arg(0)
-
-	if err := checkPath(wd, newPath); err != nil {
-		return err
+	if chdir != "" {
+		wd, err = changeDir(wd, chdir)
+		if err != nil {
+			return err
+		}
 	}
 
-	if err := gobump.WalkDir(
-		os.DirFS(wd),
-		func(path string) error {
-			switch {
-			default:
-				return nil
-			case filepath.Base(path) == "go.mod":
-				return runTransformers(path, gomodfile.UpdateModulePath(newPath))
-			case filepath.Ext(path) == ".go":
-				return runTransformers(path, gofile.UpdateImports(newPath))
-			case filepath.Ext(path) == ".proto":
-				return runTransformers(path, protofile.UpdateModulePath(newPath))
-			}
-		},
-	); err != nil {
-		return err
+	reg := builtin.NewRegistry(builtin.Config{
+		MarkdownSkipSections: splitList(mdSkipList),
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch flag.Arg(0) {
+	case "transformers":
+		return listTransformers(reg)
+	case "outdated":
+		return runOutdated(
+			ctx,
+			gobump.Options{
+				Dir:            wd,
+				Env:            env,
+				CommandTimeout: timeout,
+			},
+			jsonOut,
+			flag.Args()[1:],
+		)
+	case "deprecate":
+		return runDeprecate(ctx, wd, jsonOut, flag.Args()[1:])
 	}
 
-	if !noGoGet {
-		ok, err := shouldRunGoGet(wd, newPath)
+	var newPaths []string
+	if isShorthand(flag.Arg(0)) {
+		p, err := shorthandPath(wd, flag.Args())
 		if err != nil {
 			return err
 		}
-
-		if ok {
-			if err := runGoGet(newPath); err != nil {
-				return err
-			}
-
-			if err := runGoModTidy(); err != nil {
-				return err
-			}
+		newPaths = []string{p}
+	} else {
+		newPaths, err = readTargets(wd, listFile, flag.Args())
+		if err != nil {
+			return err
 		}
 	}
 
-	fmt.Println("done")
+	out := &output{
+		json:   jsonOut,
+		report: gobump.NewReport(newPaths...),
+	}
 
-	return nil
-}
+	if isShorthand(flag.Arg(0)) {
+		out.Printf("new module path: %s\n", newPaths[0])
+	}
 
-func checkPath(wd, path string) error {
-	if err := module.CheckPath(path); err != nil {
-		return fmt.Errorf("invalid module path %q: %w", path, err)
+	if strategy != strategyPath && strategy != strategySubdir {
+		return fmt.Errorf("unknown strategy '%s'", strategy)
 	}
 
-	mp, dr, err := gobump.ParseModules(wd)
-	if err != nil {
-		return err
+	// The paths are checked before the branch is created and the module is
+	// copied, so that a bad path leaves nothing behind.
+	if err := gobump.CheckPaths(wd, newPaths); err != nil {
+		return out.Finish(err)
 	}
 
-	pn, po := modulePrefix(mp), modulePrefix(path)
-	if pn == po {
-		return nil
+	subdir := strategy == strategySubdir
+	var subdirName string
+	if subdir {
+		subdirName, err = checkSubdir(wd, newPaths)
+		if err != nil {
+			return out.Finish(err)
+		}
 	}
 
-	for _, m := range dr {
-		if pd := modulePrefix(m); pd == po {
-			return nil
+	var branch *gitBranch
+	if useGit {
+		branch, err = prepareGit(ctx, wd, newPaths, force, out)
+		if err != nil {
+			return out.Finish(err)
 		}
 	}
 
-	return fmt.Errorf(
-		"module path '%s' does not match module '%s' or any of its direct dependencies",
-		path,
-		mp,
+	var copyChanges []transformers.Change
+	if subdir {
+		wd, copyChanges, err = copyToSubdir(ctx, wd, subdirName, newPaths, out)
+		if err != nil {
+			abandon(ctx, branch, out)
+			return out.Finish(err)
+		}
+	}
+
+	report, err := gobump.Bump(ctx, gobump.Options{
+		Dir:            wd,
+		NewPaths:       newPaths,
+		Registry:       reg,
+		Workers:        workers,
+		KeepGoing:      keepGoing,
+		NoGoGet:        noGoGet,
+		Offline:        offline,
+		Verify:         doVerify,
+		VerifyTests:    withTests,
+		Env:            env,
+		CommandTimeout: timeout,
+		Logger:         log.New(os.Stderr, "", 0),
+		OnChange:       out.Change,
+		OnWarning:      out.Warning,
+		OnFailed:       out.Failed,
+		OnNote:         out.Note,
+		OnCommandStart: out.CommandStart,
+		OnCommand:      out.Command,
+		OnRemaining:    out.Remaining,
+		OnVerify:       out.Verify,
+	})
+
+	// The report of the update is the one rendered, along with the changes
+	// made to the copy of the module.
+	out.report = report
+	for _, c := range copyChanges {
+		report.AddChange(c)
+	}
+
+	// The update is kept and committed even if it breaks the build or leaves
+	// some files behind, so that the manual fixes can be made on top of it.
+	var (
+		verifyErr *gobump.VerifyError
+		failedErr *gobump.FilesFailedError
 	)
-}
+	kept := err == nil || errors.As(err, &verifyErr) || errors.As(err, &failedErr)
 
-func runGoGet(module string) error {
-	args := []string{"go", "get", fmt.Sprintf("%s@latest", module)}
+	if subdir && !kept {
+		if rerr := os.RemoveAll(wd); rerr != nil {
+			out.Printf("cannot remove the copy in %s: %s\n", wd, rerr)
+		} else {
+			out.Printf("removed the copy in %s/\n", subdirName)
+		}
+	}
 
-	fmt.Printf("running '%s'...", strings.Join(args, " "))
+	if branch != nil && kept {
+		if cerr := commitBump(ctx, branch.repo, wd, subdir, out); cerr != nil {
+			return out.Finish(cerr)
+		}
+	}
 
-	out, err := exec.Command(args[0], args[1:]...).CombinedOutput()
-	os.Stderr.Write(out)
-	if err != nil {
-		return fmt.Errorf("error running '%s': %v", strings.Join(args, " "), err)
+	if !kept {
+		abandon(ctx, branch, out)
 	}
 
-	return nil
+	return out.Finish(err)
 }
 
-func runGoModTidy() error {
-	fmt.Println("running 'go mod tidy'...")
+// abandon abandons the branch created for the update, if any, reporting the
+// failure to do so, as the error of the update is the one returned.
+func abandon(ctx context.Context, b *gitBranch, out *output) {
+	if b == nil {
+		return
+	}
 
-	out, err := exec.Command("go", "mod", "tidy").CombinedOutput()
-	os.Stderr.Write(out)
-	if err != nil {
-		return fmt.Errorf("error running 'go mod tidy': %v", err)
+	// The update may have failed because of the interrupt, which must not
+	// prevent switching back.
+	if ctx.Err() != nil {
+		ctx = context.Background()
 	}
 
-	return nil
+	if err := abandonGit(ctx, b, out); err != nil {
+		out.Printf("cannot switch back to '%s': %s\n", b.orig, err)
+	}
 }
 
-func shouldRunGoGet(wd, path string) (bool, error) {
-	mp, _, err := gobump.ParseModules(wd)
+// changeDir returns the directory to run in, given relative to wd or as an
+// absolute path.
+func changeDir(wd, dir string) (string, error) {
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(wd, dir)
+	}
+
+	info, err := os.Stat(dir)
 	if err != nil {
-		return false, err
+		return "", fmt.Errorf("cannot change to directory: %w", err)
 	}
 
-	if pn, po := modulePrefix(mp), modulePrefix(path); pn == po {
-		return false, nil
+	if !info.IsDir() {
+		return "", fmt.Errorf("cannot change to directory %q: not a directory", dir)
 	}
 
-	// The module path is assumed to be a direct dependency of the module.
-	return true, nil
+	return dir, nil
 }
 
-func modulePrefix(path string) string {
-	pfx, _, ok := module.SplitPathVersion(path)
-	if !ok {
-		return ""
+// readTargets returns the new module paths given as the arguments and listed
+// in the file, if any. The file is given relative to wd or as an absolute
+// path.
+func readTargets(wd, file string, args []string) ([]string, error) {
+	res := append([]string(nil), args...)
+
+	if file != "" {
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(wd, file)
+		}
+
+		f, err := os.Open(file)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+
+		pp, err := gobump.ReadTargets(f)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, pp...)
+	}
+
+	if len(res) == 0 {
+		return nil, errors.New("no module path given")
+	}
+
+	return res, nil
+}
+
+// splitList splits a comma-separated list ignoring empty elements.
+func splitList(s string) []string {
+	var res []string
+	for _, e := range strings.Split(s, ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			res = append(res, e)
+		}
 	}
 
-	return pfx
+	return res
 }
 
 func usage() {
@@ -156,7 +306,13 @@ func usage() {
 This tool allows managing the major version in the Go module paths. The module
 path can be the path of the module itself or one of the module's direct dependencies.
 
-usage: gobump [flags] <new go module path>
+usage: gobump [flags] <new go module path>...
+       gobump [flags] -f <file>
+       gobump [flags] up|down [dependency]
+       gobump [flags] to <vN> [dependency]
+       gobump outdated [-json]
+       gobump deprecate [-retract <versions>]... [-retract-reason <text>] <new go module path>
+       gobump transformers
 
 `,
 	)
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestChangeDir(t *testing.T) {
+	wd := t.TempDir()
+	dir := filepath.Join(wd, "services", "api")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	file := filepath.Join(wd, "bumps.txt")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "should resolve relative directory",
+			dir:  "./services/api",
+			want: dir,
+		},
+		{
+			name: "should accept absolute directory",
+			dir:  dir,
+			want: dir,
+		},
+		{
+			name:    "should return an error if the path is a file",
+			dir:     "bumps.txt",
+			wantErr: true,
+		},
+		{
+			name:    "should return an error if the directory does not exist",
+			dir:     "services/web",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := changeDir(wd, tt.dir)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("changeDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Fatalf("changeDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadTargets(t *testing.T) {
+	wd := t.TempDir()
+	dir := filepath.Join(wd, "services", "api")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(
+		filepath.Join(dir, "bumps.txt"),
+		[]byte("# dependencies\nexample.org/foo/v2\n\nexample.org/bar/v3 # next\n"),
+		0o644,
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	chdir, err := changeDir(wd, "services/api")
+	if err != nil {
+		t.Fatalf("changeDir() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "should read file relative to the directory",
+			file: "bumps.txt",
+			args: []string{"example.org/baz/v2"},
+			want: []string{"example.org/baz/v2", "example.org/foo/v2", "example.org/bar/v3"},
+		},
+		{
+			name: "should read file given as absolute path",
+			file: filepath.Join(dir, "bumps.txt"),
+			want: []string{"example.org/foo/v2", "example.org/bar/v3"},
+		},
+		{
+			name:    "should not read file relative to the original directory",
+			file:    "services/api/bumps.txt",
+			wantErr: true,
+		},
+		{
+			name:    "should return an error if no module path is given",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTargets(chdir, tt.file, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readTargets() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("readTargets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
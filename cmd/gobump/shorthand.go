@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
+	"golang.org/x/mod/semver"
+)
+
+// isShorthand reports if the command computes the new module path from the
+// current one.
+func isShorthand(cmd string) bool {
+	switch cmd {
+	case "up", "down", "to":
+		return true
+	default:
+		return false
+	}
+}
+
+// shorthandPath computes the new module path of the module located in wd or
+// of its direct dependency from one of the following commands:
+//
+//	up [dependency]
+//	down [dependency]
+//	to <vN> [dependency]
+func shorthandPath(wd string, args []string) (string, error) {
+	cmd, args := args[0], args[1:]
+
+	var target string
+	if cmd == "to" {
+		if len(args) == 0 {
+			return "", fmt.Errorf("usage: gobump to <vN> [dependency]")
+		}
+		target, args = args[0], args[1:]
+	}
+
+	if len(args) > 1 {
+		return "", fmt.Errorf("usage: gobump %s [dependency]", cmd)
+	}
+
+	var dependency string
+	if len(args) == 1 {
+		dependency = args[0]
+	}
+
+	current, err := gobump.ResolvePath(wd, dependency)
+	if err != nil {
+		return "", err
+	}
+
+	major, err := pathx.Major(current)
+	if err != nil {
+		return "", err
+	}
+
+	// The "+incompatible" versions of v2+ modules are required using the
+	// path without the major version suffix.
+	versions, err := gobump.ParseRequiredVersions(wd)
+	if err != nil {
+		return "", err
+	}
+
+	if v := versions[current]; gomodfile.IsIncompatible(v) {
+		major, _ = strconv.Atoi(strings.TrimPrefix(semver.Major(v), "v"))
+	}
+
+	switch cmd {
+	case "up":
+		major++
+	case "down":
+		if major == 1 {
+			return "", fmt.Errorf("module path '%s' has no lower major version", current)
+		}
+		major--
+	case "to":
+		n, err := strconv.Atoi(strings.TrimPrefix(target, "v"))
+		if err != nil || !strings.HasPrefix(target, "v") || n < 1 {
+			return "", fmt.Errorf("invalid major version '%s', expected v1, v2, etc", target)
+		}
+		if n == major {
+			return "", fmt.Errorf("module path '%s' is already at %s", current, target)
+		}
+		major = n
+	}
+
+	return pathx.WithMajor(current, major)
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
+)
+
+// The major version strategies of the module itself.
+const (
+	// strategyPath updates the module path in place.
+	strategyPath = "path"
+	// strategySubdir copies the module into the major version subdirectory
+	// and updates the copy, leaving the original module intact.
+	strategySubdir = "subdir"
+)
+
+// checkSubdir checks that the module located in wd can be copied into the
+// major version subdirectory for the update to the new module paths. It
+// returns the name of the subdirectory, such as "v2".
+func checkSubdir(wd string, newPaths []string) (string, error) {
+	if len(newPaths) != 1 {
+		return "", fmt.Errorf("the %s strategy accepts a single module path", strategySubdir)
+	}
+	newPath := newPaths[0]
+
+	mp, _, err := gobump.ParseModules(wd)
+	if err != nil {
+		return "", err
+	}
+
+	if pathx.ModulePrefix(mp) != pathx.ModulePrefix(newPath) {
+		return "", fmt.Errorf(
+			"the %s strategy only applies to the module '%s' itself",
+			strategySubdir,
+			mp,
+		)
+	}
+
+	current, err := pathx.Major(mp)
+	if err != nil {
+		return "", err
+	}
+
+	major, err := pathx.Major(newPath)
+	if err != nil {
+		return "", err
+	}
+
+	if major <= current {
+		return "", fmt.Errorf(
+			"the %s strategy requires a major version higher than v%d",
+			strategySubdir,
+			current,
+		)
+	}
+
+	name := fmt.Sprintf("v%d", major)
+	if _, err := os.Stat(filepath.Join(wd, name)); err == nil {
+		return "", fmt.Errorf("directory %q already exists", filepath.Join(wd, name))
+	}
+
+	return name, nil
+}
+
+// copyToSubdir copies the module located in wd into the subdirectory with the
+// given name, checked with checkSubdir. The relative paths of the replace
+// directives of the copy are updated to point to the same directories. It
+// returns the path of the subdirectory, which is removed if the copy fails,
+// and the changes made to the copy.
+func copyToSubdir(
+	ctx context.Context,
+	wd, name string,
+	newPaths []string,
+	out *output,
+) (string, []transformers.Change, error) {
+	dir := filepath.Join(wd, name)
+
+	if err := gobump.CopyModule(ctx, os.DirFS(wd), dir); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	reg := transformers.Registration{
+		Name: "gomod-replace",
+		New: func([]string) transformers.FileTransformer {
+			return gomodfile.RebaseReplacements("..")
+		},
+	}
+
+	var changes []transformers.Change
+	if _, err := gobump.TransformFile(
+		gobump.DirFS(dir),
+		transformers.Context{
+			Path: "go.mod",
+			OnChange: func(c transformers.Change) {
+				changes = append(changes, c)
+				out.Change(c)
+			},
+		},
+		newPaths,
+		reg,
+	); err != nil {
+		os.RemoveAll(dir)
+		return "", nil, err
+	}
+
+	out.Printf("copied module into %s/, updating the copy\n", name)
+
+	return dir, changes, nil
+}
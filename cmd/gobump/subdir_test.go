@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danilvpetrov/gobump"
+)
+
+func TestCopyToSubdir(t *testing.T) {
+	wd := t.TempDir()
+	if err := os.WriteFile(filepath.Join(wd, "go.mod"), []byte(`module example.org/foo
+
+go 1.20
+
+require example.org/bar v1.0.0
+
+replace example.org/bar => ../bar
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	newPaths := []string{"example.org/foo/v2"}
+
+	name, err := checkSubdir(wd, newPaths)
+	if err != nil {
+		t.Fatalf("checkSubdir() error = %v", err)
+	}
+
+	if name != "v2" {
+		t.Fatalf("checkSubdir() = %q, want %q", name, "v2")
+	}
+
+	out := &output{json: true, report: gobump.NewReport(newPaths...)}
+
+	dir, _, err := copyToSubdir(context.Background(), wd, name, newPaths, out)
+	if err != nil {
+		t.Fatalf("copyToSubdir() error = %v", err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `module example.org/foo
+
+go 1.20
+
+require example.org/bar v1.0.0
+
+replace example.org/bar => ../../bar
+`
+	if string(bb) != want {
+		t.Fatalf("copyToSubdir() wrote go.mod %q, want %q", bb, want)
+	}
+
+	if _, err := checkSubdir(wd, newPaths); err == nil {
+		t.Fatal("checkSubdir() error = nil for an existing subdirectory, want error")
+	}
+}
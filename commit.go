@@ -0,0 +1,68 @@
+package gobump
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"golang.org/x/mod/module"
+)
+
+// BranchName returns the name of the git branch for the update to the given
+// module paths, such as "gobump/v3" for a single path or
+// "gobump/foo-v3-bar-v2" for several paths.
+func BranchName(newPaths []string) string {
+	if len(newPaths) == 1 {
+		if n, err := pathx.Major(newPaths[0]); err == nil {
+			return fmt.Sprintf("gobump/v%d", n)
+		}
+	}
+
+	var parts []string
+	for _, p := range newPaths {
+		pfx, _, ok := module.SplitPathVersion(p)
+		n, err := pathx.Major(p)
+		if !ok || err != nil {
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%s-v%d", path.Base(pfx), n))
+	}
+
+	return "gobump/" + strings.Join(parts, "-")
+}
+
+// CommitMessage returns the message of the commit of the update described by
+// the report. The versions are the versions of the updated dependencies pinned
+// by 'go get', keyed by the module path.
+func CommitMessage(r *Report, versions map[string]string) string {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	var b strings.Builder
+
+	if len(r.NewPaths) == 1 {
+		fmt.Fprintf(&b, "Update module path to %s\n", r.NewPaths[0])
+	} else {
+		fmt.Fprintf(&b, "Update %d module paths\n", len(r.NewPaths))
+	}
+
+	b.WriteString("\n")
+	for _, p := range r.NewPaths {
+		if v := versions[p]; v != "" {
+			fmt.Fprintf(&b, "- %s %s\n", p, v)
+		} else {
+			fmt.Fprintf(&b, "- %s\n", p)
+		}
+	}
+
+	if len(r.Files) > 0 {
+		b.WriteString("\nRewritten files:\n\n")
+		for _, f := range r.Files {
+			fmt.Fprintf(&b, "- %s\n", f.Path)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package gobump_test
+
+import (
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestBranchName(t *testing.T) {
+	tests := []struct {
+		name     string
+		newPaths []string
+		want     string
+	}{
+		{
+			name:     "single path",
+			newPaths: []string{"example.org/foo/v3"},
+			want:     "gobump/v3",
+		},
+		{
+			name:     "single gopkg.in path",
+			newPaths: []string{"gopkg.in/yaml.v3"},
+			want:     "gobump/v3",
+		},
+		{
+			name:     "several paths",
+			newPaths: []string{"example.org/foo/v3", "example.org/bar"},
+			want:     "gobump/foo-v3-bar-v1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BranchName(tt.newPaths); got != tt.want {
+				t.Errorf("BranchName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitMessage(t *testing.T) {
+	r := NewReport("example.org/foo/v3", "example.org/bar/v2")
+	r.AddChange(transformers.Change{Path: "go.mod", Old: "example.org/bar", New: "example.org/bar/v2"})
+	r.AddChange(transformers.Change{Path: "cmd/main.go", Old: "example.org/foo", New: "example.org/foo/v3"})
+
+	got := CommitMessage(r, map[string]string{"example.org/bar/v2": "v2.1.0"})
+	want := "Update 2 module paths\n" +
+		"\n" +
+		"- example.org/foo/v3\n" +
+		"- example.org/bar/v2 v2.1.0\n" +
+		"\n" +
+		"Rewritten files:\n" +
+		"\n" +
+		"- go.mod\n" +
+		"- cmd/main.go\n"
+
+	if got != want {
+		t.Fatalf("CommitMessage() = %q, want %q", got, want)
+	}
+}
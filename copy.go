@@ -0,0 +1,87 @@
+package gobump
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// CopyModule copies the files of the Go module located in the root of fsys to
+// the dst directory, which must not exist.
+//
+// The files ignored by WalkDir and the files of the nested modules are not
+// copied. The file permissions are preserved.
+func CopyModule(ctx context.Context, fsys fs.FS, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return fmt.Errorf("directory %q already exists", dst)
+	}
+
+	var (
+		files  []string
+		nested = map[string]bool{}
+	)
+
+	// The files are collected before copying, so that the walk does not
+	// visit the copy if dst is located within fsys.
+	if err := WalkDir(ctx, fsys, func(file string) error {
+		if !isNestedModule(fsys, path.Dir(file), nested) {
+			files = append(files, file)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := copyFile(fsys, f, filepath.Join(dst, filepath.FromSlash(f))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// isNestedModule reports if the directory belongs to a module nested in the
+// module located in the root of fsys. The results are cached per directory.
+func isNestedModule(fsys fs.FS, dir string, cache map[string]bool) bool {
+	if dir == "." {
+		return false
+	}
+
+	if res, ok := cache[dir]; ok {
+		return res
+	}
+
+	_, err := fs.Stat(fsys, path.Join(dir, "go.mod"))
+	res := err == nil || isNestedModule(fsys, path.Dir(dir), cache)
+	cache[dir] = res
+
+	return res
+}
+
+// copyFile copies the file from fsys to the dst path, creating its parent
+// directories.
+func copyFile(fsys fs.FS, file, dst string) error {
+	info, err := fs.Stat(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	bb, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(dst, bb, info.Mode().Perm())
+}
@@ -0,0 +1,68 @@
+package gobump_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestCopyModule(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":                 {Data: []byte("module example.org/foo\n")},
+		"foo.go":                 {Data: []byte("package foo\n")},
+		"cmd/foo/main.go":        {Data: []byte("package main\n"), Mode: 0o755},
+		"testdata/data.txt":      {Data: []byte("data\n")},
+		".git/config":            {Data: []byte("[core]\n")},
+		"_example/main.go":       {Data: []byte("package main\n")},
+		"tools/go.mod":           {Data: []byte("module example.org/foo/tools\n")},
+		"tools/sub/tools.go":     {Data: []byte("package sub\n")},
+		"internal/bar/bar.go":    {Data: []byte("package bar\n")},
+		"internal/bar/README.md": {Data: []byte("# bar\n")},
+	}
+
+	dst := filepath.Join(t.TempDir(), "v2")
+	if err := CopyModule(context.Background(), fsys, dst); err != nil {
+		t.Fatalf("CopyModule() error = %v", err)
+	}
+
+	var got []string
+	if err := filepath.WalkDir(dst, func(p string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dst, p)
+		got = append(got, filepath.ToSlash(rel))
+
+		return err
+	}); err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+
+	want := []string{
+		"cmd/foo/main.go",
+		"foo.go",
+		"go.mod",
+		"internal/bar/README.md",
+		"internal/bar/bar.go",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("CopyModule() copied %v, want %v", got, want)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dst, "foo.go"))
+	if err != nil || string(bb) != "package foo\n" {
+		t.Fatalf("CopyModule() copied foo.go = %q, %v", bb, err)
+	}
+
+	if err := CopyModule(context.Background(), fsys, dst); err == nil {
+		t.Fatalf("CopyModule() error = nil, want an error if the destination exists")
+	}
+}
@@ -0,0 +1,362 @@
+package gobump
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WriteFS is a file system that allows writing the files. The files are read
+// and written using slash-separated paths as per fs.ValidPath.
+type WriteFS interface {
+	fs.FS
+
+	// WriteFile writes the data to the named file, creating it with the
+	// given permissions if necessary. The permissions of the existing files
+	// are left intact.
+	WriteFile(name string, data []byte, perm fs.FileMode) error
+}
+
+// DirFS returns a WriteFS for the tree of files rooted at the directory dir
+// on the disk.
+func DirFS(dir string) WriteFS {
+	return dirFS{
+		FS:  os.DirFS(dir),
+		dir: dir,
+	}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (d dirFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	return os.WriteFile(filepath.Join(d.dir, filepath.FromSlash(name)), data, perm)
+}
+
+// isDirFS reports if the file system is the one returned by DirFS for the
+// directory dir, so that the files written to it are seen by the go commands
+// run in dir.
+func isDirFS(fsys fs.FS, dir string) bool {
+	d, ok := fsys.(dirFS)
+	return ok && filepath.Clean(d.dir) == filepath.Clean(dir)
+}
+
+// MemFS is an in-memory WriteFS. The directories are implied by the paths of
+// the files.
+//
+// The zero value is an empty file system. It is safe for concurrent use.
+type MemFS struct {
+	m     sync.RWMutex
+	files map[string]*memFile
+
+	// dirs maps the directories to the sorted names of their entries, so
+	// that the directories are read without scanning all the files.
+	dirs map[string][]string
+}
+
+// memFile is a file of MemFS.
+type memFile struct {
+	data    []byte
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+// NewMemFS returns a new in-memory file system with the given file contents,
+// keyed by the slash-separated paths.
+func NewMemFS(files map[string][]byte) *MemFS {
+	m := &MemFS{}
+	for name, data := range files {
+		m.write(name, data, 0o644)
+	}
+
+	return m
+}
+
+// Open opens the named file.
+func (m *MemFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	// The opened files keep referring to their content, which is never
+	// modified in place, so they are safe to read after the lock is released.
+	if f, ok := m.files[name]; ok {
+		return &openMemFile{
+			Reader: bytes.NewReader(f.data),
+			info:   f.info(path.Base(name)),
+		}, nil
+	}
+
+	names, ok := m.dirs[name]
+	if !ok && name != "." {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	entries := make([]fs.DirEntry, 0, len(names))
+	for _, n := range names {
+		info := memDirInfo(n)
+		if f, ok := m.files[path.Join(name, n)]; ok {
+			info = f.info(n)
+		}
+		entries = append(entries, fs.FileInfoToDirEntry(info))
+	}
+
+	return &openMemDir{
+		info:    memDirInfo(path.Base(name)),
+		entries: entries,
+	}, nil
+}
+
+// WriteFile writes the data to the named file.
+func (m *MemFS) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !fs.ValidPath(name) || name == "." {
+		return &fs.PathError{Op: "write", Path: name, Err: fs.ErrInvalid}
+	}
+
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	m.write(name, data, perm)
+
+	return nil
+}
+
+// Remove removes the named file. The directories left empty are removed as
+// well.
+func (m *MemFS) Remove(name string) error {
+	m.m.Lock()
+	defer m.m.Unlock()
+
+	if _, ok := m.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(m.files, name)
+
+	for name != "." {
+		dir := path.Dir(name)
+
+		names := m.dirs[dir]
+		if i := sort.SearchStrings(names, path.Base(name)); i < len(names) && names[i] == path.Base(name) {
+			names = append(names[:i], names[i+1:]...)
+		}
+
+		if len(names) > 0 || dir == "." {
+			m.dirs[dir] = names
+			break
+		}
+
+		delete(m.dirs, dir)
+		name = dir
+	}
+
+	return nil
+}
+
+// Files returns the slash-separated paths of the files, in lexical order.
+func (m *MemFS) Files() []string {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	res := make([]string, 0, len(m.files))
+	for name := range m.files {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+func (m *MemFS) write(name string, data []byte, perm fs.FileMode) {
+	if m.files == nil {
+		m.files = map[string]*memFile{}
+		m.dirs = map[string][]string{}
+	}
+
+	if f, ok := m.files[name]; ok {
+		perm = f.mode
+	} else {
+		m.index(name)
+	}
+
+	m.files[name] = &memFile{
+		data:    bytes.Clone(data),
+		mode:    perm,
+		modTime: time.Now(),
+	}
+}
+
+// index adds the new file to the entries of its directory, and the
+// directories missing from the index to the entries of their parents.
+func (m *MemFS) index(name string) {
+	for name != "." {
+		dir, elem := path.Dir(name), path.Base(name)
+
+		names := m.dirs[dir]
+		i := sort.SearchStrings(names, elem)
+		if i < len(names) && names[i] == elem {
+			return
+		}
+
+		existed := len(names) > 0
+
+		names = append(names, "")
+		copy(names[i+1:], names[i:])
+		names[i] = elem
+		m.dirs[dir] = names
+
+		// The directory already existing is indexed in its parent.
+		if existed {
+			return
+		}
+		name = dir
+	}
+}
+
+func (m *MemFS) lookup(name string) bool {
+	m.m.RLock()
+	defer m.m.RUnlock()
+
+	_, ok := m.files[name]
+	return ok
+}
+
+func (f *memFile) info(name string) memInfo {
+	return memInfo{
+		name:    name,
+		size:    int64(len(f.data)),
+		mode:    f.mode,
+		modTime: f.modTime,
+	}
+}
+
+// memDirInfo returns the information of a directory of MemFS.
+func memDirInfo(name string) memInfo {
+	return memInfo{
+		name: name,
+		mode: fs.ModeDir | 0o555,
+	}
+}
+
+// memInfo is the fs.FileInfo of the files and directories of MemFS.
+type memInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i memInfo) Name() string       { return i.name }
+func (i memInfo) Size() int64        { return i.size }
+func (i memInfo) Mode() fs.FileMode  { return i.mode }
+func (i memInfo) ModTime() time.Time { return i.modTime }
+func (i memInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i memInfo) Sys() any           { return nil }
+
+// openMemFile is an opened file of MemFS.
+type openMemFile struct {
+	*bytes.Reader
+	info memInfo
+}
+
+func (f *openMemFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *openMemFile) Close() error               { return nil }
+
+// openMemDir is an opened directory of MemFS.
+type openMemDir struct {
+	info    memInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *openMemDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *openMemDir) Close() error               { return nil }
+
+func (d *openMemDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: fs.ErrInvalid}
+}
+
+func (d *openMemDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+
+	return rest[:n], nil
+}
+
+// Overlay is a WriteFS that keeps the written files in memory on top of a
+// read-only file system, which is left intact. It allows seeing the result of
+// an update without touching the disk.
+//
+// The directories are read from the underlying file system, so their entries
+// describe the files as they are in it, and the files written to the overlay
+// but absent from it are not listed. It is safe for concurrent use.
+type Overlay struct {
+	base  fs.FS
+	edits MemFS
+}
+
+// NewOverlay returns a new overlay on top of the given file system.
+func NewOverlay(base fs.FS) *Overlay {
+	return &Overlay{base: base}
+}
+
+// Open opens the named file, preferring the written content over the
+// content of the underlying file system.
+func (o *Overlay) Open(name string) (fs.File, error) {
+	if o.edits.lookup(name) {
+		return o.edits.Open(name)
+	}
+
+	return o.base.Open(name)
+}
+
+// WriteFile writes the data to the named file in memory.
+func (o *Overlay) WriteFile(name string, data []byte, perm fs.FileMode) error {
+	if !o.edits.lookup(name) {
+		if info, err := fs.Stat(o.base, name); err == nil {
+			perm = info.Mode().Perm()
+		}
+	}
+
+	return o.edits.WriteFile(name, data, perm)
+}
+
+// Edits returns the content of the files written to the overlay, keyed by
+// the slash-separated paths.
+func (o *Overlay) Edits() map[string][]byte {
+	res := map[string][]byte{}
+	for _, name := range o.edits.Files() {
+		bb, err := fs.ReadFile(&o.edits, name)
+		if err == nil {
+			res[name] = bb
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,137 @@
+package gobump_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestDirFS(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte("package a\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := DirFS(dir)
+	if err := fsys.WriteFile("a.go", []byte("package b\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	bb, err := fs.ReadFile(fsys, "a.go")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(bb) != "package b\n" {
+		t.Fatalf("ReadFile() = %q", bb)
+	}
+
+	info, err := os.Stat(filepath.Join(dir, "a.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("WriteFile() changed permissions to %v", info.Mode().Perm())
+	}
+
+	if err := fsys.WriteFile("../a.go", nil, 0o644); err == nil {
+		t.Fatal("WriteFile() error = nil for a path outside of the directory")
+	}
+}
+
+func TestMemFS(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"go.mod":   []byte("module example.org/foo\n"),
+		"pkg/a.go": []byte("package pkg\n"),
+	})
+
+	if err := fsys.WriteFile("pkg/b.go", []byte("package pkg\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := fstest.TestFS(fsys, "go.mod", "pkg/a.go", "pkg/b.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.WriteFile("pkg/a.go", []byte("package foo\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	bb, err := fs.ReadFile(fsys, "pkg/a.go")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(bb) != "package foo\n" {
+		t.Fatalf("ReadFile() = %q", bb)
+	}
+
+	if want := []string{"go.mod", "pkg/a.go", "pkg/b.go"}; !reflect.DeepEqual(fsys.Files(), want) {
+		t.Fatalf("Files() = %v, want %v", fsys.Files(), want)
+	}
+
+	if err := fsys.WriteFile("pkg/sub/dir/c.go", []byte("package dir\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := fstest.TestFS(fsys, "go.mod", "pkg/a.go", "pkg/b.go", "pkg/sub/dir/c.go"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.Remove("pkg/sub/dir/c.go"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+
+	if _, err := fs.Stat(fsys, "pkg/sub"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Stat() error = %v for a removed directory, want fs.ErrNotExist", err)
+	}
+
+	if err := fsys.Remove("pkg/sub/dir/c.go"); !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Remove() error = %v for a missing file, want fs.ErrNotExist", err)
+	}
+
+	if err := fstest.TestFS(fsys, "go.mod", "pkg/a.go", "pkg/b.go"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOverlay(t *testing.T) {
+	base := fstest.MapFS{
+		"go.mod":   {Data: []byte("module example.org/foo\n")},
+		"pkg/a.go": {Data: []byte("package pkg\n"), Mode: 0o600},
+	}
+
+	o := NewOverlay(base)
+	if err := o.WriteFile("pkg/a.go", []byte("package foo\n"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	bb, err := fs.ReadFile(o, "pkg/a.go")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(bb) != "package foo\n" {
+		t.Fatalf("ReadFile() = %q", bb)
+	}
+
+	info, err := fs.Stat(o, "pkg/a.go")
+	if err != nil {
+		t.Fatalf("Stat() error = %v", err)
+	}
+	if info.Mode().Perm() != 0o600 {
+		t.Fatalf("WriteFile() changed permissions to %v", info.Mode().Perm())
+	}
+
+	if string(base["pkg/a.go"].Data) != "package pkg\n" {
+		t.Fatal("WriteFile() modified the underlying file system")
+	}
+
+	want := map[string][]byte{"pkg/a.go": []byte("package foo\n")}
+	if got := o.Edits(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Edits() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,149 @@
+package gobump
+
+import (
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"golang.org/x/mod/semver"
+)
+
+// RemainingModule is another major version of an updated dependency still
+// required by the other modules after the update, so that both majors end up
+// in the build list.
+type RemainingModule struct {
+	// Path is the module path of the remaining major version.
+	Path string `json:"path"`
+
+	// Version is the version of the module in the build list.
+	Version string `json:"version"`
+
+	// NewPath is the new module path of the dependency.
+	NewPath string `json:"new_path"`
+
+	// Chains is a list of the shortest chains of the requirements pulling the
+	// module in, one per requirement of the main module that does. A chain
+	// starts with the main module and ends with the remaining module, the
+	// modules are given as "path@version". The requirement of the remaining
+	// module by the main module itself is only listed if no other module
+	// requires it.
+	Chains [][]string `json:"chains"`
+}
+
+// RemainingModules returns the other major versions of the dependencies
+// updated to the new module paths that are still required according to the
+// module graph printed by 'go mod graph'.
+func RemainingModules(graph string, newPaths []string) []RemainingModule {
+	var (
+		main  string
+		nodes []string
+		edges = map[string][]string{}
+		seen  = map[string]bool{}
+	)
+	for _, l := range strings.Split(graph, "\n") {
+		// The other lines are the messages of the go command, the required
+		// modules always have versions.
+		ff := strings.Fields(l)
+		if len(ff) != 2 || !strings.Contains(ff[1], "@") {
+			continue
+		}
+
+		if main == "" {
+			main = ff[0]
+		}
+		edges[ff[0]] = append(edges[ff[0]], ff[1])
+
+		for _, n := range ff {
+			if !seen[n] {
+				seen[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+	}
+
+	var res []RemainingModule
+	for _, np := range newPaths {
+		pfx := pathx.ModulePrefix(np)
+
+		var (
+			paths    []string
+			versions = map[string]string{}
+		)
+		for _, n := range nodes {
+			p, v, ok := strings.Cut(n, "@")
+			if !ok || p == np || pathx.ModulePrefix(p) != pfx {
+				continue
+			}
+
+			if _, ok := versions[p]; !ok {
+				paths = append(paths, p)
+			}
+
+			// The build list has the highest version required.
+			if semver.Compare(v, versions[p]) > 0 {
+				versions[p] = v
+			}
+		}
+
+		for _, p := range paths {
+			m := RemainingModule{
+				Path:    p,
+				Version: versions[p],
+				NewPath: np,
+			}
+
+			var direct []string
+			for _, d := range edges[main] {
+				c := shortestChain(edges, d, p)
+				switch {
+				case c == nil:
+				case len(c) == 1:
+					direct = append([]string{main}, c...)
+				default:
+					m.Chains = append(m.Chains, append([]string{main}, c...))
+				}
+			}
+
+			// The go.mod file of the main module lists the indirect
+			// requirements too, so its own requirement only matters if no
+			// other module pulls the module in.
+			if len(m.Chains) == 0 && direct != nil {
+				m.Chains = [][]string{direct}
+			}
+
+			res = append(res, m)
+		}
+	}
+
+	return res
+}
+
+// shortestChain returns the shortest chain of the requirements from the
+// module to any version of the module with the given path, or nil if there
+// is none.
+func shortestChain(edges map[string][]string, from, modulePath string) []string {
+	prev := map[string]string{from: ""}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		n := queue[0]
+		queue = queue[1:]
+
+		if p, _, _ := strings.Cut(n, "@"); p == modulePath {
+			var res []string
+			for ; n != ""; n = prev[n] {
+				res = append([]string{n}, res...)
+			}
+
+			return res
+		}
+
+		for _, c := range edges[n] {
+			if _, ok := prev[c]; !ok {
+				prev[c] = n
+				queue = append(queue, c)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package gobump_test
+
+import (
+	"reflect"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestRemainingModules(t *testing.T) {
+	graph := `go: downloading example.org/baz v1.2.0
+example.org/app example.org/bar/v2@v2.1.0
+example.org/app example.org/baz@v1.2.0
+example.org/app example.org/qux@v1.0.0
+example.org/app example.org/bar@v1.1.0
+example.org/app example.org/old@v1.0.0
+example.org/app go@1.20
+example.org/baz@v1.2.0 example.org/lib@v0.3.0
+example.org/baz@v1.2.0 example.org/bar@v1.0.0
+example.org/lib@v0.3.0 example.org/bar@v1.1.0
+example.org/qux@v1.0.0 example.org/lib@v0.3.0
+example.org/qux@v1.0.0 gopkg.in/yaml.v2@v2.4.0
+`
+
+	tests := []struct {
+		name     string
+		newPaths []string
+		want     []RemainingModule
+	}{
+		{
+			name:     "should return the chains through every direct requirement",
+			newPaths: []string{"example.org/bar/v2"},
+			want: []RemainingModule{
+				{
+					Path:    "example.org/bar",
+					Version: "v1.1.0",
+					NewPath: "example.org/bar/v2",
+					Chains: [][]string{
+						{"example.org/app", "example.org/baz@v1.2.0", "example.org/bar@v1.0.0"},
+						{"example.org/app", "example.org/qux@v1.0.0", "example.org/lib@v0.3.0", "example.org/bar@v1.1.0"},
+					},
+				},
+			},
+		},
+		{
+			name:     "should handle gopkg.in paths",
+			newPaths: []string{"gopkg.in/yaml.v3"},
+			want: []RemainingModule{
+				{
+					Path:    "gopkg.in/yaml.v2",
+					Version: "v2.4.0",
+					NewPath: "gopkg.in/yaml.v3",
+					Chains: [][]string{
+						{"example.org/app", "example.org/qux@v1.0.0", "gopkg.in/yaml.v2@v2.4.0"},
+					},
+				},
+			},
+		},
+		{
+			name:     "should return the requirement of the main module if no other module requires it",
+			newPaths: []string{"example.org/old/v2"},
+			want: []RemainingModule{
+				{
+					Path:    "example.org/old",
+					Version: "v1.0.0",
+					NewPath: "example.org/old/v2",
+					Chains: [][]string{
+						{"example.org/app", "example.org/old@v1.0.0"},
+					},
+				},
+			},
+		},
+		{
+			name:     "should return nothing if no other major is required",
+			newPaths: []string{"example.org/none/v2"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RemainingModules(graph, tt.newPaths); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("RemainingModules() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+// Package git provides the operations on git repositories required to commit
+// a module path update. It shells out to the git command.
+package git
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrNotRepository is returned if the directory is not within a git
+// repository.
+var ErrNotRepository = errors.New("not a git repository")
+
+// Repo is a git repository.
+type Repo struct {
+	// Dir is the top-level directory of the repository working tree.
+	Dir string
+}
+
+// Open returns the repository the directory belongs to. It returns
+// ErrNotRepository if the directory is not within a git repository.
+func Open(ctx context.Context, dir string) (*Repo, error) {
+	out, err := run(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, ErrNotRepository
+		}
+		return nil, err
+	}
+
+	return &Repo{Dir: strings.TrimSpace(out)}, nil
+}
+
+// IsDirty reports if the working tree has uncommitted changes, including the
+// untracked files.
+func (r *Repo) IsDirty(ctx context.Context) (bool, error) {
+	out, err := run(ctx, r.Dir, "status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(out) != "", nil
+}
+
+// CreateBranch creates a new branch starting at the current commit and
+// switches to it.
+func (r *Repo) CreateBranch(ctx context.Context, name string) error {
+	_, err := run(ctx, r.Dir, "checkout", "-b", name)
+	return err
+}
+
+// CurrentBranch returns the name of the current branch.
+func (r *Repo) CurrentBranch(ctx context.Context) (string, error) {
+	out, err := run(ctx, r.Dir, "rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Head returns the hash of the current commit.
+func (r *Repo) Head(ctx context.Context) (string, error) {
+	out, err := run(ctx, r.Dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(out), nil
+}
+
+// Checkout switches to the branch or commit. The uncommitted changes are
+// carried over if they do not conflict with it.
+func (r *Repo) Checkout(ctx context.Context, ref string) error {
+	_, err := run(ctx, r.Dir, "checkout", "-q", ref)
+	return err
+}
+
+// DeleteBranch deletes the branch, even if it is not merged.
+func (r *Repo) DeleteBranch(ctx context.Context, name string) error {
+	_, err := run(ctx, r.Dir, "branch", "-D", name)
+	return err
+}
+
+// Discard discards the uncommitted changes of the working tree, including
+// the untracked files that are not ignored.
+func (r *Repo) Discard(ctx context.Context) error {
+	if _, err := run(ctx, r.Dir, "reset", "-q", "--hard"); err != nil {
+		return err
+	}
+
+	_, err := run(ctx, r.Dir, "clean", "-q", "-f", "-d")
+	return err
+}
+
+// Commit stages the given paths and commits them with the message. The paths
+// are relative to dir. The changes of other paths are not committed.
+func (r *Repo) Commit(ctx context.Context, dir, message string, paths ...string) error {
+	if len(paths) == 0 {
+		return errors.New("nothing to commit")
+	}
+
+	args := append([]string{"add", "-A", "--"}, paths...)
+	if _, err := run(ctx, dir, args...); err != nil {
+		return err
+	}
+
+	args = append([]string{"commit", "-m", message, "--"}, paths...)
+	_, err := run(ctx, dir, args...)
+
+	return err
+}
+
+// run runs the git command in the directory and returns its standard output.
+func run(ctx context.Context, dir string, args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf(
+			"error running 'git %s': %w: %s",
+			args[0],
+			err,
+			strings.TrimSpace(stderr.String()),
+		)
+	}
+
+	return stdout.String(), nil
+}
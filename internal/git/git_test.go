@@ -0,0 +1,184 @@
+package git_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/internal/git"
+)
+
+// initRepo creates a git repository with a single commit in a temporary
+// directory.
+func initRepo(t *testing.T) string {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	gitCmd(t, dir, "init", "-q", "-b", "main")
+	gitCmd(t, dir, "config", "user.name", "test")
+	gitCmd(t, dir, "config", "user.email", "test@example.org")
+	writeFile(t, dir, "go.mod", "module example.org/foo\n")
+	gitCmd(t, dir, "add", "-A")
+	gitCmd(t, dir, "commit", "-q", "-m", "initial")
+
+	return dir
+}
+
+func gitCmd(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+
+	p := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := initRepo(t)
+	writeFile(t, dir, "sub/dir/file.go", "package dir\n")
+
+	r, err := Open(context.Background(), filepath.Join(dir, "sub", "dir"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	want, _ := filepath.EvalSymlinks(dir)
+	got, _ := filepath.EvalSymlinks(r.Dir)
+	if got != want {
+		t.Fatalf("Open() dir = %v, want %v", got, want)
+	}
+
+	if _, err := Open(context.Background(), t.TempDir()); !errors.Is(err, ErrNotRepository) {
+		t.Fatalf("Open() error = %v, want %v", err, ErrNotRepository)
+	}
+}
+
+func TestRepo_IsDirty(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	r, err := Open(ctx, dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if dirty, err := r.IsDirty(ctx); err != nil || dirty {
+		t.Fatalf("IsDirty() = %v, %v, want false", dirty, err)
+	}
+
+	writeFile(t, dir, "new.go", "package foo\n")
+
+	if dirty, err := r.IsDirty(ctx); err != nil || !dirty {
+		t.Fatalf("IsDirty() = %v, %v, want true", dirty, err)
+	}
+}
+
+func TestRepo_Commit(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	r, err := Open(ctx, dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	if err := r.CreateBranch(ctx, "gobump/v2"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	if b, err := r.CurrentBranch(ctx); err != nil || b != "gobump/v2" {
+		t.Fatalf("CurrentBranch() = %v, %v, want gobump/v2", b, err)
+	}
+
+	writeFile(t, dir, "go.mod", "module example.org/foo/v2\n")
+	writeFile(t, dir, "v2/foo.go", "package foo\n")
+	writeFile(t, dir, "unrelated.txt", "manual change\n")
+
+	if err := r.Commit(ctx, dir, "Bump to v2", "go.mod", "v2"); err != nil {
+		t.Fatalf("Commit() error = %v", err)
+	}
+
+	if got := gitCmd(t, dir, "log", "-1", "--format=%s"); got != "Bump to v2" {
+		t.Fatalf("Commit() message = %q", got)
+	}
+
+	files := gitCmd(t, dir, "show", "--name-only", "--format=")
+	if want := "go.mod\nv2/foo.go"; files != want {
+		t.Fatalf("Commit() committed %q, want %q", files, want)
+	}
+
+	if got := gitCmd(t, dir, "status", "--porcelain"); got != "?? unrelated.txt" {
+		t.Fatalf("Commit() left status %q", got)
+	}
+}
+
+func TestRepo_DeleteBranch(t *testing.T) {
+	dir := initRepo(t)
+	ctx := context.Background()
+
+	r, err := Open(ctx, dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	head, err := r.Head(ctx)
+	if err != nil {
+		t.Fatalf("Head() error = %v", err)
+	}
+
+	if err := r.CreateBranch(ctx, "gobump/v2"); err != nil {
+		t.Fatalf("CreateBranch() error = %v", err)
+	}
+
+	writeFile(t, dir, "go.mod", "module example.org/foo/v2\n")
+	writeFile(t, dir, "v2/foo.go", "package foo\n")
+
+	if err := r.Discard(ctx); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+
+	if dirty, err := r.IsDirty(ctx); err != nil || dirty {
+		t.Fatalf("IsDirty() = %v, %v, want false", dirty, err)
+	}
+
+	if err := r.Checkout(ctx, "main"); err != nil {
+		t.Fatalf("Checkout() error = %v", err)
+	}
+
+	if err := r.DeleteBranch(ctx, "gobump/v2"); err != nil {
+		t.Fatalf("DeleteBranch() error = %v", err)
+	}
+
+	if got := gitCmd(t, dir, "branch", "--format=%(refname:short)"); got != "main" {
+		t.Fatalf("DeleteBranch() left branches %q, want main", got)
+	}
+
+	if got, err := r.Head(ctx); err != nil || got != head {
+		t.Fatalf("Head() = %v, %v, want %v", got, err, head)
+	}
+}
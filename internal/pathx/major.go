@@ -0,0 +1,123 @@
+package pathx
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// IsPathMajor reports if the given string complies with path major
+// requirements.
+//
+// See this link for reference: https://go.dev/ref/mod#major-version-suffixes.
+func IsPathMajor(in string) bool {
+	switch len(in) {
+	case 0, 1:
+		return false
+	case 2:
+		return in[0] == 'v' && in[1] >= '2' && in[1] <= '9'
+	default:
+		if in[0] != 'v' || in[1] < '1' || in[1] > '9' {
+			return false
+		}
+
+		for _, l := range in[2:] {
+			if l < '0' || l > '9' {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ModulePrefix returns the module path without the major version suffix, or
+// an empty string if the module path is invalid.
+func ModulePrefix(modulePath string) string {
+	pfx, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return ""
+	}
+
+	return pfx
+}
+
+// ModulePrefixes returns the module paths without the major version
+// suffixes. It returns an error if any of the module paths is invalid.
+func ModulePrefixes(modulePaths []string) ([]string, error) {
+	res := make([]string, 0, len(modulePaths))
+	for _, p := range modulePaths {
+		pfx, _, ok := module.SplitPathVersion(p)
+		if !ok {
+			return nil, fmt.Errorf("module path %s is invalid", p)
+		}
+
+		res = append(res, pfx)
+	}
+
+	return res, nil
+}
+
+// ContainsAny reports if the source mentions any of the module path
+// prefixes. It is used to skip the files that cannot refer to the module
+// before parsing them.
+func ContainsAny(src []byte, pfxs []string) bool {
+	for _, pfx := range pfxs {
+		if bytes.Contains(src, []byte(pfx)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Major returns the major version of the module path. The module paths
+// without the major version suffix are reported as v1, as v0 and v1 modules
+// share the same path.
+//
+// It returns an error if the module path is invalid.
+func Major(modulePath string) (int, error) {
+	_, pathMajor, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return 0, fmt.Errorf("module path %s is invalid", modulePath)
+	}
+
+	if pathMajor == "" {
+		return 1, nil
+	}
+
+	n, err := strconv.Atoi(strings.TrimLeft(pathMajor, "/.v"))
+	if err != nil {
+		return 0, fmt.Errorf("module path %s is invalid", modulePath)
+	}
+
+	return n, nil
+}
+
+// WithMajor returns the module path with the major version replaced by the
+// given one. The gopkg.in module paths use the ".vN" suffix for all the major
+// versions, others use the "/vN" suffix starting from v2.
+//
+// It returns an error if the module path or the major version is invalid.
+func WithMajor(modulePath string, major int) (string, error) {
+	pfx, _, ok := module.SplitPathVersion(modulePath)
+	if !ok {
+		return "", fmt.Errorf("module path %s is invalid", modulePath)
+	}
+
+	if major < 1 {
+		return "", fmt.Errorf("major version v%d is invalid", major)
+	}
+
+	switch {
+	case strings.HasPrefix(pfx, "gopkg.in/"):
+		return fmt.Sprintf("%s.v%d", pfx, major), nil
+	case major == 1:
+		return pfx, nil
+	default:
+		return fmt.Sprintf("%s/v%d", pfx, major), nil
+	}
+}
@@ -0,0 +1,128 @@
+package pathx_test
+
+import (
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/internal/pathx"
+)
+
+func TestIsPathMajor(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want bool
+	}{
+		{name: "valid major version suffix", in: "v2", want: true},
+		{name: "valid major version suffix (significant version number)", in: "v12345", want: true},
+		{name: "invalid major version suffix", in: "v1", want: false},
+		{name: "invalid major version suffix (first char correct followed by a non-digit)", in: "vv", want: false},
+		{name: "invalid major version suffix (short string)", in: "v", want: false},
+		{name: "invalid major version suffix (long string looks like major versoin)", in: "v12345notvalid", want: false},
+		{name: "invalid major version suffix (only non-digit characters)", in: "<invalid-major-version>", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPathMajor(tt.in); got != tt.want {
+				t.Errorf("IsPathMajor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModulePrefix(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		want       string
+	}{
+		{name: "path without major version", modulePath: "example.org/foo", want: "example.org/foo"},
+		{name: "path with major version", modulePath: "example.org/foo/v3", want: "example.org/foo"},
+		{name: "gopkg.in path", modulePath: "gopkg.in/yaml.v2", want: "gopkg.in/yaml"},
+		{name: "invalid path", modulePath: "example.org/foo/v1", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ModulePrefix(tt.modulePath); got != tt.want {
+				t.Errorf("ModulePrefix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContainsAny(t *testing.T) {
+	pfxs := []string{"example.org/foo", "example.org/bar"}
+
+	tests := []struct {
+		name string
+		src  string
+		want bool
+	}{
+		{name: "first prefix", src: "import \"example.org/foo/sub\"", want: true},
+		{name: "second prefix", src: "// example.org/bar", want: true},
+		{name: "no prefix", src: "import \"example.org/baz\"", want: false},
+		{name: "empty source", src: "", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ContainsAny([]byte(tt.src), pfxs); got != tt.want {
+				t.Errorf("ContainsAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMajor(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		want       int
+		wantErr    bool
+	}{
+		{name: "path without major version", modulePath: "example.org/foo", want: 1},
+		{name: "path with major version", modulePath: "example.org/foo/v3", want: 3},
+		{name: "gopkg.in path", modulePath: "gopkg.in/yaml.v2", want: 2},
+		{name: "invalid path", modulePath: "example.org/foo/v1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Major(tt.modulePath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Major() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Major() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMajor(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		major      int
+		want       string
+		wantErr    bool
+	}{
+		{name: "upgrade to v2", modulePath: "example.org/foo", major: 2, want: "example.org/foo/v2"},
+		{name: "upgrade to v5", modulePath: "example.org/foo/v2", major: 5, want: "example.org/foo/v5"},
+		{name: "downgrade to v1", modulePath: "example.org/foo/v2", major: 1, want: "example.org/foo"},
+		{name: "upgrade gopkg.in path", modulePath: "gopkg.in/yaml.v2", major: 3, want: "gopkg.in/yaml.v3"},
+		{name: "downgrade gopkg.in path", modulePath: "gopkg.in/yaml.v2", major: 1, want: "gopkg.in/yaml.v1"},
+		{name: "invalid major version", modulePath: "example.org/foo", major: 0, wantErr: true},
+		{name: "invalid path", modulePath: "example.org/foo/v1", major: 2, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WithMajor(tt.modulePath, tt.major)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WithMajor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("WithMajor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package pathx
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/mod/module"
+)
+
+// UpdateImportPath updates Golang package import path to the new module. If no
+// updates were performed to the import path, ok is returned as false and the
+// new import path is returned as empty string.
+//
+// The import path is only updated if it belongs to a major version of the new
+// module. The prefix of the module path must match whole path elements of the
+// import path. If the paths of the other known modules are given, the import
+// path is attributed to the module with the longest matching path, so that
+// the imports of the nested modules sharing the prefix are left intact.
+//
+// It returns an error if the given module path is invalid.
+func UpdateImportPath(
+	newModule, importPath string,
+	modules ...string,
+) (_ string, ok bool, _ error) {
+	if newModule == "" || importPath == "" || newModule == importPath {
+		return "", false, nil
+	}
+
+	pfx, vp, ok := module.SplitPathVersion(newModule)
+	if !ok {
+		return "", false, fmt.Errorf("module path %s is invalid", newModule)
+	}
+
+	if owner, ok := Owner(importPath, modules); ok {
+		if op, _, _ := module.SplitPathVersion(owner); op != pfx {
+			return "", false, nil
+		}
+	}
+
+	if strings.HasPrefix(newModule, "gopkg.in/") {
+		// gopkg.in paths carry the major version in the last element of the
+		// module path.
+		rest := strings.TrimPrefix(importPath, pfx)
+		if rest == importPath || !strings.HasPrefix(rest, ".v") {
+			return "", false, nil
+		}
+
+		i := strings.IndexByte(rest, '/')
+		if i < 0 {
+			i = len(rest)
+		}
+
+		np := newModule + rest[i:]
+		if np == importPath {
+			return "", false, nil
+		}
+
+		return np, true, nil
+	}
+
+	if !HasPathPrefix(importPath, pfx) {
+		return "", false, nil
+	}
+
+	ee := pathElementsAfterPrefix(pfx, importPath)
+	if len(ee) > 0 {
+		if IsPathMajor(ee[0]) {
+			ee[0] = vp
+		} else {
+			for _, el := range ee[1:] {
+				// If the position of the major version in the original import
+				// path does not match to the path, bail out.
+				if IsPathMajor(el) {
+					return "", false, nil
+				}
+			}
+
+			ee = append([]string{vp}, ee...)
+		}
+	} else {
+		ee = []string{vp}
+	}
+
+	ee = append([]string{pfx}, ee...)
+
+	np := path.Join(ee...)
+	if np == importPath {
+		return "", false, nil
+	}
+
+	return np, true, nil
+}
+
+// UpdateImportPathAny updates the import path to the first of the new modules
+// the import path belongs to, so that the paths of several modules are
+// updated in a single pass. See UpdateImportPath for details.
+func UpdateImportPathAny(
+	newModules []string,
+	importPath string,
+	modules ...string,
+) (_ string, ok bool, _ error) {
+	for _, m := range newModules {
+		np, ok, err := UpdateImportPath(m, importPath, modules...)
+		if err != nil || ok {
+			return np, ok, err
+		}
+	}
+
+	return "", false, nil
+}
+
+func pathElementsAfterPrefix(prefix, path string) []string {
+	pfxEls, pathEls := strings.Split(prefix, "/"),
+		strings.Split(path, "/")
+
+	return pathEls[len(pfxEls):]
+}
+
+// HasPathPrefix reports if the path starts with the prefix matching whole
+// path elements, so that "example.org/foo" is a prefix of "example.org/foo/bar"
+// but not of "example.org/foobar".
+func HasPathPrefix(path, prefix string) bool {
+	if !strings.HasPrefix(path, prefix) {
+		return false
+	}
+
+	return len(path) == len(prefix) || path[len(prefix)] == '/'
+}
+
+// Owner returns the path of the module owning the package with the given
+// import path, which is the longest module path that is a path prefix of the
+// import path. It returns false if none of the modules owns the package.
+func Owner(importPath string, modules []string) (string, bool) {
+	var owner string
+	for _, m := range modules {
+		if len(m) > len(owner) && HasPathPrefix(importPath, m) {
+			owner = m
+		}
+	}
+
+	return owner, owner != ""
+}
+
+// AssumedPackageName returns the package name assumed from the import path,
+// following the conventions of goimports: the major version suffix and the
+// "go-" prefix are ignored, and the name ends at the first character that
+// cannot be used in identifiers.
+func AssumedPackageName(importPath string) string {
+	elems := strings.Split(importPath, "/")
+
+	name := elems[len(elems)-1]
+	if len(elems) > 1 && IsPathMajor(name) {
+		name = elems[len(elems)-2]
+	}
+
+	name = strings.TrimPrefix(name, "go-")
+	if i := strings.IndexFunc(name, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_')
+	}); i >= 0 {
+		name = name[:i]
+	}
+
+	return name
+}
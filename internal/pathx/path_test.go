@@ -0,0 +1,273 @@
+package pathx_test
+
+import (
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/internal/pathx"
+)
+
+func TestUpdateImportPath(t *testing.T) {
+	tests := []struct {
+		name       string
+		newModule  string
+		importPath string
+		modules    []string
+		want       string
+		wantOK     bool
+		wantErr    bool
+	}{
+		{
+			name:       "v1 -> v2 (no subdirs)",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/foo/bar",
+			want:       "example.org/foo/bar/v2",
+			wantOK:     true,
+		},
+		{
+			name:       "v1 -> v2 (with subdirs)",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/foo/bar/sub/dir",
+			want:       "example.org/foo/bar/v2/sub/dir",
+			wantOK:     true,
+		},
+		{
+			name:       "v2 -> v3 (no subdirs)",
+			newModule:  "example.org/foo/bar/v3",
+			importPath: "example.org/foo/bar/v2",
+			want:       "example.org/foo/bar/v3",
+			wantOK:     true,
+		},
+		{
+			name:       "v2 -> v3 (with subdirs)",
+			newModule:  "example.org/foo/bar/v3",
+			importPath: "example.org/foo/bar/v2/sub/dir",
+			want:       "example.org/foo/bar/v3/sub/dir",
+			wantOK:     true,
+		},
+		{
+			name:       "v3.2.1+incompatible -> v4 (with subdirs)",
+			newModule:  "example.org/foo/bar/v4",
+			importPath: "example.org/foo/bar/sub/dir",
+			want:       "example.org/foo/bar/v4/sub/dir",
+			wantOK:     true,
+		},
+		{
+			name:       "v2 -> v1 (no subdirs)",
+			newModule:  "example.org/foo/bar",
+			importPath: "example.org/foo/bar/v2",
+			want:       "example.org/foo/bar",
+			wantOK:     true,
+		},
+		{
+			name:       "v2 -> v1 (with subdirs)",
+			newModule:  "example.org/foo/bar",
+			importPath: "example.org/foo/bar/v2/sub/dir",
+			want:       "example.org/foo/bar/sub/dir",
+			wantOK:     true,
+		},
+		{
+			name:       "no match",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/bar/foo",
+			wantOK:     false,
+		},
+		{
+			name:       "new path equal to old",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/foo/bar/v2",
+			wantOK:     false,
+		},
+		{
+			name:       "new path equal to old (with subdirs)",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/foo/bar/v2/sub/dir",
+			wantOK:     false,
+		},
+		{
+			name:       "new module is empty",
+			newModule:  "",
+			importPath: "example.org/foo/bar",
+			wantOK:     false,
+		},
+		{
+			name:       "import path is empty",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "",
+			wantOK:     false,
+		},
+		{
+			name:       "partially matching but major versions in different positions",
+			newModule:  "example.org/foo/bar/v2",
+			importPath: "example.org/foo/bar/foobar/v3",
+			wantOK:     false,
+		},
+		{
+			name:       "invalid new module",
+			newModule:  "example.org/foo/bar/v1",
+			importPath: "example.org/foo/bar",
+			wantErr:    true,
+		},
+		{
+			name:       "gopkg.in/ module path v1 -> v2",
+			newModule:  "gopkg.in/yaml.v2",
+			importPath: "gopkg.in/yaml.v1",
+			want:       "gopkg.in/yaml.v2",
+			wantOK:     true,
+		},
+		{
+			name:       "gopkg.in/ module path v2 -> v1",
+			newModule:  "gopkg.in/yaml.v1",
+			importPath: "gopkg.in/yaml.v2",
+			want:       "gopkg.in/yaml.v1",
+			wantOK:     true,
+		},
+		{
+			name:       "gopkg.in/ module path with subdirs",
+			newModule:  "gopkg.in/src-d/go-git.v5",
+			importPath: "gopkg.in/src-d/go-git.v4/plumbing",
+			want:       "gopkg.in/src-d/go-git.v5/plumbing",
+			wantOK:     true,
+		},
+		{
+			name:       "gopkg.in/ module path sharing the prefix",
+			newModule:  "gopkg.in/yaml.v3",
+			importPath: "gopkg.in/yamlx.v2",
+			wantOK:     false,
+		},
+		{
+			name:       "module path sharing the prefix",
+			newModule:  "example.org/foo/v2",
+			importPath: "example.org/foobar/baz",
+			wantOK:     false,
+		},
+		{
+			name:       "nested module sharing the prefix",
+			newModule:  "example.org/foo/v2",
+			importPath: "example.org/foo/sub/pkg",
+			modules:    []string{"example.org/foo", "example.org/foo/sub"},
+			wantOK:     false,
+		},
+		{
+			name:       "package of the module next to a nested module",
+			newModule:  "example.org/foo/v2",
+			importPath: "example.org/foo/subpkg",
+			modules:    []string{"example.org/foo", "example.org/foo/sub"},
+			want:       "example.org/foo/v2/subpkg",
+			wantOK:     true,
+		},
+		{
+			name:       "package of the major version owned by the module",
+			newModule:  "example.org/foo/v3",
+			importPath: "example.org/foo/v2/sub/pkg",
+			modules:    []string{"example.org/foo/v2", "example.org/foo/sub"},
+			want:       "example.org/foo/v3/sub/pkg",
+			wantOK:     true,
+		},
+		{
+			name:       "gopkg.in/ no match",
+			newModule:  "gopkg.in/yaml.v2",
+			importPath: "example.org/foo/bar",
+			wantOK:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := UpdateImportPath(tt.newModule, tt.importPath, tt.modules...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateImportPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("UpdateImportPath() ok = %v, wantOK %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Fatalf("UpdateImportPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOwner(t *testing.T) {
+	modules := []string{
+		"example.org/foo",
+		"example.org/foo/sub",
+		"example.org/foo/v2",
+	}
+
+	tests := []struct {
+		importPath string
+		want       string
+		wantOK     bool
+	}{
+		{importPath: "example.org/foo", want: "example.org/foo", wantOK: true},
+		{importPath: "example.org/foo/pkg", want: "example.org/foo", wantOK: true},
+		{importPath: "example.org/foo/sub/pkg", want: "example.org/foo/sub", wantOK: true},
+		{importPath: "example.org/foo/subpkg", want: "example.org/foo", wantOK: true},
+		{importPath: "example.org/foo/v2/sub", want: "example.org/foo/v2", wantOK: true},
+		{importPath: "example.org/foobar", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, ok := Owner(tt.importPath, modules)
+			if ok != tt.wantOK {
+				t.Fatalf("Owner() ok = %v, wantOK %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Fatalf("Owner() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateImportPathAny(t *testing.T) {
+	newModules := []string{
+		"example.org/foo/v2",
+		"example.org/bar/v3",
+	}
+
+	tests := []struct {
+		importPath string
+		want       string
+		wantOK     bool
+	}{
+		{importPath: "example.org/foo/pkg", want: "example.org/foo/v2/pkg", wantOK: true},
+		{importPath: "example.org/bar/v2/pkg", want: "example.org/bar/v3/pkg", wantOK: true},
+		{importPath: "example.org/foo/v2/pkg", wantOK: false},
+		{importPath: "example.org/baz/pkg", wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			got, ok, err := UpdateImportPathAny(newModules, tt.importPath)
+			if err != nil {
+				t.Fatalf("UpdateImportPathAny() error = %v", err)
+			}
+			if ok != tt.wantOK {
+				t.Fatalf("UpdateImportPathAny() ok = %v, wantOK %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Fatalf("UpdateImportPathAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssumedPackageName(t *testing.T) {
+	tests := []struct {
+		importPath string
+		want       string
+	}{
+		{importPath: "fmt", want: "fmt"},
+		{importPath: "example.org/foo/bar", want: "bar"},
+		{importPath: "example.org/foo/v2", want: "foo"},
+		{importPath: "example.org/foo/v2/bar", want: "bar"},
+		{importPath: "example.org/go-foo", want: "foo"},
+		{importPath: "example.org/foo-bar", want: "foo"},
+		{importPath: "gopkg.in/yaml.v3", want: "yaml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.importPath, func(t *testing.T) {
+			if got := AssumedPackageName(tt.importPath); got != tt.want {
+				t.Fatalf("AssumedPackageName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+module example.com/foo/bar/v2
+
+go 1.20
+
+require (
+	example.com/foobar/foobar/v3 v3.0.0
+	example.com/barfoo/barfoo v1.0.0
+	example.com/barfoo/barfoo/v2 v2.0.0
+	gopkg.in/yaml.v2 v2.4.0
+)
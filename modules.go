@@ -1,11 +1,20 @@
 package gobump
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
 	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
 )
 
 // ParseModules parses go modules in the go.mod of the Go module. This function
@@ -15,31 +24,289 @@ import (
 // For more info on the go.mod file structure refer to this resource:
 // https://go.dev/doc/modules/gomod-ref.
 func ParseModules(moduleDir string) (string, []string, error) {
-	p := filepath.Join(moduleDir, "go.mod")
-	bb, err := os.ReadFile(p)
+	mf, err := readModFile(os.DirFS(moduleDir), moduleDir)
 	if err != nil {
-		return "", nil, fmt.Errorf(
+		return "", nil, err
+	}
+
+	return mf.Module.Mod.Path, directRequires(mf), nil
+}
+
+// parseModules parses the content of the go.mod file p located in the
+// directory dir like ParseModules does.
+func parseModules(dir, p string, bb []byte) (string, []string, error) {
+	mf, err := parseModFile(dir, p, bb)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return mf.Module.Mod.Path, directRequires(mf), nil
+}
+
+// readModFile reads and parses the go.mod file at the root of the file
+// system holding the module located in the directory dir.
+func readModFile(fsys fs.FS, dir string) (*modfile.File, error) {
+	bb, err := fs.ReadFile(fsys, "go.mod")
+	if err != nil {
+		return nil, fmt.Errorf(
 			"failed to open go.mod file in %q directory: %s",
-			moduleDir,
+			dir,
 			err,
 		)
 	}
 
+	return parseModFile(dir, filepath.Join(dir, "go.mod"), bb)
+}
+
+// parseModFile parses the content of the go.mod file p located in the
+// directory dir.
+func parseModFile(dir, p string, bb []byte) (*modfile.File, error) {
 	mf, err := modfile.Parse(p, bb, nil)
 	if err != nil {
-		return "", nil, fmt.Errorf(
+		return nil, fmt.Errorf(
 			"invalid go.mod file in %q directory: %s",
-			moduleDir,
+			dir,
 			err,
 		)
 	}
 
-	var directRequires []string
+	if mf.Module == nil {
+		return nil, fmt.Errorf(
+			"invalid go.mod file in %q directory: no module directive",
+			dir,
+		)
+	}
+
+	return mf, nil
+}
+
+// directRequires returns the paths of the modules required directly.
+func directRequires(mf *modfile.File) []string {
+	var res []string
 	for _, req := range mf.Require {
 		if !req.Indirect {
-			directRequires = append(directRequires, req.Mod.Path)
+			res = append(res, req.Mod.Path)
+		}
+	}
+
+	return res
+}
+
+// ParseRequiredVersions parses the go.mod of the Go module and returns the
+// versions of all the required modules, keyed by the module path.
+func ParseRequiredVersions(moduleDir string) (map[string]string, error) {
+	mf, err := readModFile(os.DirFS(moduleDir), moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return requiredVersions(mf), nil
+}
+
+// requiredVersions returns the versions of all the modules required in the
+// go.mod file, keyed by the module path.
+func requiredVersions(mf *modfile.File) map[string]string {
+	res := map[string]string{}
+	for _, req := range mf.Require {
+		res[req.Mod.Path] = req.Mod.Version
+	}
+
+	return res
+}
+
+// IncompatibleVersion returns the "+incompatible" version of the module with
+// the same path prefix as newPath required by the Go module located in
+// moduleDir, such as "v3.2.1+incompatible". It returns an empty string if
+// there is no such requirement, or if newPath is the path of the required
+// module itself.
+func IncompatibleVersion(moduleDir, newPath string) (string, error) {
+	pfx, _, ok := module.SplitPathVersion(newPath)
+	if !ok {
+		return "", fmt.Errorf("module path %s is invalid", newPath)
+	}
+
+	if pfx == newPath {
+		return "", nil
+	}
+
+	mf, err := readModFile(os.DirFS(moduleDir), moduleDir)
+	if err != nil {
+		return "", err
+	}
+
+	return incompatibleVersion(mf, newPath), nil
+}
+
+// incompatibleVersion returns the "+incompatible" version of the module with
+// the same path prefix as newPath required in the go.mod file like
+// IncompatibleVersion does. The path is assumed to be valid.
+func incompatibleVersion(mf *modfile.File, newPath string) string {
+	pfx, _, _ := module.SplitPathVersion(newPath)
+	if pfx == newPath {
+		return ""
+	}
+
+	for _, req := range mf.Require {
+		if req.Mod.Path == pfx && gomodfile.IsIncompatible(req.Mod.Version) {
+			return req.Mod.Version
 		}
 	}
 
-	return mf.Module.Mod.Path, directRequires, nil
+	return ""
+}
+
+// FindModules returns the paths of all the modules declared by the go.mod
+// files in the file system, including the nested modules, in the walk order.
+// The files ignored by the go command are skipped as per WalkDir.
+func FindModules(ctx context.Context, fsys fs.FS) ([]string, error) {
+	var files []string
+	err := WalkDir(ctx, fsys, func(p string) error {
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return modulePaths(fsys, files)
+}
+
+// modulePaths returns the paths of the modules declared by the go.mod files
+// among the given files of the file system.
+func modulePaths(fsys fs.FS, files []string) ([]string, error) {
+	var res []string
+	for _, p := range files {
+		if path.Base(p) != "go.mod" {
+			continue
+		}
+
+		bb, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return nil, err
+		}
+
+		mp := modfile.ModulePath(bb)
+		if mp == "" {
+			return nil, fmt.Errorf("invalid go.mod file %q: no module directive", p)
+		}
+
+		res = append(res, mp)
+	}
+
+	return res, nil
+}
+
+// ModuleResolver resolves the Go modules owning the files in a directory tree.
+//
+// It is safe for concurrent use.
+type ModuleResolver struct {
+	fsys  fs.FS
+	m     sync.Mutex
+	cache map[string]*transformers.Module
+}
+
+// NewModuleResolver returns a new resolver of the modules in the directory
+// tree with the given root.
+func NewModuleResolver(root string) *ModuleResolver {
+	return NewModuleResolverFS(os.DirFS(root))
+}
+
+// NewModuleResolverFS returns a new resolver of the modules in the file
+// system.
+func NewModuleResolverFS(fsys fs.FS) *ModuleResolver {
+	return &ModuleResolver{
+		fsys:  fsys,
+		cache: map[string]*transformers.Module{},
+	}
+}
+
+// Owner returns the module owning the file with the given slash-separated
+// path relative to the root. The owning module is the one declared by the
+// closest go.mod file in the file's directory or its parents within the root.
+//
+// It returns nil if no module owns the file.
+func (r *ModuleResolver) Owner(file string) (*transformers.Module, error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.owner(path.Dir(file))
+}
+
+func (r *ModuleResolver) owner(dir string) (*transformers.Module, error) {
+	if m, ok := r.cache[dir]; ok {
+		return m, nil
+	}
+
+	var m *transformers.Module
+
+	p := path.Join(dir, "go.mod")
+	bb, err := fs.ReadFile(r.fsys, p)
+	switch {
+	case err == nil:
+		mp, dr, err := parseModules(dir, p, bb)
+		if err != nil {
+			return nil, err
+		}
+
+		m = &transformers.Module{
+			Dir:      dir,
+			Path:     mp,
+			Requires: dr,
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case dir != ".":
+		m, err = r.owner(path.Dir(dir))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r.cache[dir] = m
+
+	return m, nil
+}
+
+// ResolvePath returns the path of the module located in moduleDir if
+// dependency is empty, or the path of its direct dependency otherwise. The
+// dependency can be given with or without the major version suffix, as long
+// as it refers to a single direct dependency.
+func ResolvePath(moduleDir, dependency string) (string, error) {
+	mp, requires, err := ParseModules(moduleDir)
+	if err != nil {
+		return "", err
+	}
+
+	if dependency == "" {
+		return mp, nil
+	}
+
+	pfx, _, ok := module.SplitPathVersion(dependency)
+	if !ok {
+		return "", fmt.Errorf("module path %s is invalid", dependency)
+	}
+
+	var matches []string
+	for _, r := range requires {
+		if r == dependency {
+			return r, nil
+		}
+
+		if rp, _, ok := module.SplitPathVersion(r); ok && rp == pfx {
+			matches = append(matches, r)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", fmt.Errorf("module '%s' is not a direct dependency of '%s'", dependency, mp)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", fmt.Errorf(
+			"module '%s' is ambiguous, it matches direct dependencies: %s",
+			dependency,
+			strings.Join(matches, ", "),
+		)
+	}
 }
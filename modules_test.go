@@ -1,8 +1,12 @@
 package gobump_test
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+	"testing/fstest"
 
 	. "github.com/danilvpetrov/gobump"
 )
@@ -53,3 +57,212 @@ func TestParseModules(t *testing.T) {
 		})
 	}
 }
+
+func TestModuleResolver_Owner(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		wantPath string
+		wantDir  string
+		wantNil  bool
+		wantErr  bool
+	}{
+		{
+			name:     "should resolve module owning a file in its root",
+			file:     "dira/main.go",
+			wantPath: "example.com/foo/bar",
+			wantDir:  "dira",
+		},
+		{
+			name:     "should resolve module owning a file in its subdirectory",
+			file:     "dira/sub/dir/main.go",
+			wantPath: "example.com/foo/bar",
+			wantDir:  "dira",
+		},
+		{
+			name:    "should return nil if no module owns the file",
+			file:    "dirb/main.go",
+			wantNil: true,
+		},
+		{
+			name:    "should return error if go.mod is invalid",
+			file:    "dirc/main.go",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewModuleResolver("internal/testdata/modules")
+
+			got, err := r.Owner(tt.file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Owner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if (got == nil) != tt.wantNil {
+				t.Fatalf("Owner() = %v, wantNil %v", got, tt.wantNil)
+			}
+
+			if got != nil && (got.Path != tt.wantPath || got.Dir != tt.wantDir) {
+				t.Fatalf("Owner() = %+v, want path %v and dir %v", got, tt.wantPath, tt.wantDir)
+			}
+		})
+	}
+}
+
+func TestNewModuleResolverFS(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"go.mod":         []byte("module example.com/foo\n\nrequire example.com/bar v1.0.0\n"),
+		"sub/go.mod":     []byte("module example.com/foo/sub\n"),
+		"sub/pkg/pkg.go": []byte("package pkg\n"),
+	})
+
+	r := NewModuleResolverFS(fsys)
+
+	got, err := r.Owner("sub/pkg/pkg.go")
+	if err != nil {
+		t.Fatalf("Owner() error = %v", err)
+	}
+	if got == nil || got.Path != "example.com/foo/sub" || got.Dir != "sub" {
+		t.Fatalf("Owner() = %+v, want example.com/foo/sub in sub", got)
+	}
+
+	got, err = r.Owner("main.go")
+	if err != nil {
+		t.Fatalf("Owner() error = %v", err)
+	}
+	if got == nil || got.Path != "example.com/foo" || !reflect.DeepEqual(got.Requires, []string{"example.com/bar"}) {
+		t.Fatalf("Owner() = %+v, want example.com/foo requiring example.com/bar", got)
+	}
+}
+
+func TestResolvePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name: "should resolve the module itself",
+			want: "example.com/foo/bar/v2",
+		},
+		{
+			name:       "should resolve a dependency by its prefix",
+			dependency: "example.com/foobar/foobar",
+			want:       "example.com/foobar/foobar/v3",
+		},
+		{
+			name:       "should resolve a dependency by its exact path",
+			dependency: "example.com/barfoo/barfoo/v2",
+			want:       "example.com/barfoo/barfoo/v2",
+		},
+		{
+			name:       "should resolve a gopkg.in dependency",
+			dependency: "gopkg.in/yaml.v3",
+			want:       "gopkg.in/yaml.v2",
+		},
+		{
+			name:       "should return error if the dependency is ambiguous",
+			dependency: "example.com/barfoo/barfoo/v3",
+			wantErr:    true,
+		},
+		{
+			name:       "should return error if the dependency is not required",
+			dependency: "example.com/unknown",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolvePath("internal/testdata/modules/dird", tt.dependency)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolvePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("ResolvePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIncompatibleVersion(t *testing.T) {
+	dir := t.TempDir()
+
+	gomod := "module example.com/app\n" +
+		"\n" +
+		"require (\n" +
+		"\texample.com/foo v3.2.1+incompatible\n" +
+		"\texample.com/bar v1.0.0\n" +
+		")\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		newPath string
+		want    string
+	}{
+		{
+			name:    "should detect +incompatible requirement",
+			newPath: "example.com/foo/v4",
+			want:    "v3.2.1+incompatible",
+		},
+		{
+			name:    "should ignore updates to the unversioned path",
+			newPath: "example.com/foo",
+		},
+		{
+			name:    "should ignore compatible requirements",
+			newPath: "example.com/bar/v2",
+		},
+		{
+			name:    "should ignore modules that are not required",
+			newPath: "example.com/baz/v2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := IncompatibleVersion(dir, tt.newPath)
+			if err != nil {
+				t.Fatalf("IncompatibleVersion() error = %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("IncompatibleVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindModules(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":              {Data: []byte("module example.com/foo\n")},
+		"main.go":             {Data: []byte("package main\n")},
+		"sub/go.mod":          {Data: []byte("module example.com/foo/sub\n")},
+		"testdata/go.mod":     {Data: []byte("module example.com/foo/testdata\n")},
+		"tools/v2/go.mod":     {Data: []byte("module example.com/foo/tools/v2\n")},
+		"tools/v2/tools.go":   {Data: []byte("package tools\n")},
+		"_ignored/sub/go.mod": {Data: []byte("module example.com/ignored\n")},
+	}
+
+	got, err := FindModules(context.Background(), fsys)
+	if err != nil {
+		t.Fatalf("FindModules() error = %v", err)
+	}
+
+	want := []string{
+		"example.com/foo",
+		"example.com/foo/sub",
+		"example.com/foo/tools/v2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("FindModules() = %v, want %v", got, want)
+	}
+}
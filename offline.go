@@ -0,0 +1,434 @@
+package gobump
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// offlineEnv asks the go command for the directory of the module cache and
+// the flags it is configured with, including the ones set with 'go env -w'.
+// It returns the directory and the environment the go commands are run with
+// in the offline mode, so that they use the module cache and never reach the
+// network.
+func offlineEnv(ctx context.Context, opts Options, r *Report) (string, []string, error) {
+	out, err := runInternalCommand(ctx, opts, r, opts.Dir, "go", "env", "GOMODCACHE", "GOFLAGS")
+	if err != nil {
+		return "", nil, err
+	}
+
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 2 || strings.TrimSpace(lines[0]) == "" {
+		return "", nil, fmt.Errorf("cannot determine the module cache directory from the 'go env' output %q", out)
+	}
+
+	// The flags given are kept, except for the ones selecting the module
+	// mode.
+	var flags []string
+	for _, f := range strings.Fields(lines[1]) {
+		if !strings.HasPrefix(f, "-mod=") && !strings.HasPrefix(f, "--mod=") {
+			flags = append(flags, f)
+		}
+	}
+	flags = append(flags, "-mod=mod")
+
+	env := append(
+		opts.Env[:len(opts.Env):len(opts.Env)],
+		"GOFLAGS="+strings.Join(flags, " "),
+		"GOPROXY=off",
+	)
+
+	return strings.TrimSpace(lines[0]), env, nil
+}
+
+// LatestCachedVersion returns the version of the module that 'go get' would
+// resolve "latest" to among the versions available in the module cache
+// located in cacheDir: the highest release version, or the highest
+// pre-release version if there are no releases.
+//
+// It returns an empty string if the cache has no version of the module.
+func LatestCachedVersion(cacheDir, modulePath string) (string, error) {
+	dir, err := cacheVersionDir(cacheDir, modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	_, pathMajor, _ := module.SplitPathVersion(modulePath)
+
+	var latest string
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), ".mod")
+		if !ok {
+			continue
+		}
+
+		v, err := module.UnescapeVersion(name)
+		if err != nil || !semver.IsValid(v) || module.CheckPathMajor(v, pathMajor) != nil {
+			continue
+		}
+
+		// The packages of the module are only available with its source.
+		if _, err := os.Stat(filepath.Join(dir, name+".zip")); err != nil {
+			continue
+		}
+
+		if latest == "" || isLater(v, latest) {
+			latest = v
+		}
+	}
+
+	return latest, nil
+}
+
+// isLater reports if the version v is preferred over the version w as the
+// "latest" version, that is the releases are preferred over pre-releases.
+func isLater(v, w string) bool {
+	if pv, pw := semver.Prerelease(v) != "", semver.Prerelease(w) != ""; pv != pw {
+		return pw
+	}
+
+	return semver.Compare(v, w) > 0
+}
+
+// vendoredVersion returns the version of the module listed in the
+// vendor/modules.txt file of the module located in dir, or an empty string if
+// the module is not vendored.
+func vendoredVersion(dir, modulePath string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, "vendor", "modules.txt"))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// The module lines are "# path version" optionally followed by a
+		// replacement.
+		ff := strings.Fields(s.Text())
+		if len(ff) >= 3 && ff[0] == "#" && ff[1] == modulePath {
+			return ff[2], nil
+		}
+	}
+
+	return "", s.Err()
+}
+
+// offlineUpdate is the update of the dependencies done without accessing the
+// network.
+type offlineUpdate struct {
+	// cacheDir is the directory of the module cache.
+	cacheDir string
+
+	// versions is the versions of the new module paths of the dependencies,
+	// keyed by the module paths.
+	versions map[string]string
+}
+
+// resolveOffline resolves the versions of the new module paths of the
+// dependencies of the module located in dir from its vendor directory or the
+// module cache located in cacheDir.
+func resolveOffline(dir, cacheDir string, deps []string) (*offlineUpdate, error) {
+	u := &offlineUpdate{
+		cacheDir: cacheDir,
+		versions: map[string]string{},
+	}
+	for _, d := range deps {
+		v, err := vendoredVersion(dir, d)
+		if err != nil {
+			return nil, err
+		}
+
+		if v == "" {
+			v, err = LatestCachedVersion(cacheDir, d)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if v == "" {
+			return nil, fmt.Errorf(
+				"module %s is not found in the module cache at %s or in the vendor directory, "+
+					"run 'go mod download %s@latest' on a host with network access to add it to the cache",
+				d, cacheDir, d,
+			)
+		}
+
+		u.versions[d] = v
+	}
+
+	return u, nil
+}
+
+// requireOffline replaces the requirements of the previous major versions of
+// the dependencies with the requirements of the given versions in the go.mod
+// file of the module located in dir. It returns the replaced requirements.
+func requireOffline(dir string, versions map[string]string, report func(transformers.Change)) ([]module.Version, error) {
+	p := filepath.Join(dir, "go.mod")
+	bb, err := os.ReadFile(p)
+	if err != nil {
+		return nil, err
+	}
+
+	mf, err := modfile.Parse(p, bb, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dropped []module.Version
+		paths   []string
+	)
+	for np := range versions {
+		paths = append(paths, np)
+	}
+	sort.Strings(paths)
+
+	for _, np := range paths {
+		nv := versions[np]
+
+		var old *modfile.Require
+		for _, r := range mf.Require {
+			if r.Mod.Path != np && pathx.ModulePrefix(r.Mod.Path) == pathx.ModulePrefix(np) {
+				old = r
+				break
+			}
+		}
+
+		if old == nil {
+			if err := mf.AddRequire(np, nv); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		dropped = append(dropped, old.Mod)
+		start := old.Syntax.Start
+		report(transformers.Change{
+			Path:   "go.mod",
+			Line:   start.Line,
+			Column: start.LineRune,
+			Old:    old.Mod.Path + " " + old.Mod.Version,
+			New:    np + " " + nv,
+		})
+
+		if err := mf.DropRequire(old.Mod.Path); err != nil {
+			return nil, err
+		}
+
+		// The new path may already be required along with the old one, in
+		// which case its requirement is updated.
+		if err := mf.AddRequire(np, nv); err != nil {
+			return nil, err
+		}
+	}
+
+	mf.Cleanup()
+
+	bb, err = mf.Format()
+	if err != nil {
+		return nil, err
+	}
+
+	return dropped, os.WriteFile(p, bb, 0o644)
+}
+
+// sumOffline updates the go.sum file of the module located in dir. The lines
+// of the dropped modules no longer required by the modules available in the
+// module cache are removed, and the lines of the required modules available
+// in the cache are added.
+func sumOffline(dir, cacheDir string, dropped []module.Version) error {
+	bb, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		return err
+	}
+
+	mf, err := modfile.Parse("go.mod", bb, nil)
+	if err != nil {
+		return err
+	}
+
+	var queue []module.Version
+	for _, r := range mf.Require {
+		queue = append(queue, r.Mod)
+	}
+
+	// The graph of the requirements is walked through the go.mod files in the
+	// cache. The modules missing from the cache are left to the go command.
+	required := map[module.Version]bool{}
+	var sums []string
+	for len(queue) > 0 {
+		m := queue[0]
+		queue = queue[1:]
+
+		if required[m] {
+			continue
+		}
+		required[m] = true
+
+		ms, zs, reqs, err := cachedSums(cacheDir, m)
+		if err != nil {
+			return err
+		}
+
+		if ms != "" {
+			sums = append(sums, fmt.Sprintf("%s %s/go.mod %s", m.Path, m.Version, ms))
+		}
+		if zs != "" {
+			sums = append(sums, fmt.Sprintf("%s %s %s", m.Path, m.Version, zs))
+		}
+		queue = append(queue, reqs...)
+	}
+
+	isDropped := map[string]bool{}
+	for _, m := range dropped {
+		isDropped[m.Path] = true
+	}
+
+	p := filepath.Join(dir, "go.sum")
+	bb, err = os.ReadFile(p)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	lines := map[string]bool{}
+	for _, l := range strings.Split(string(bb), "\n") {
+		ff := strings.Fields(l)
+		if len(ff) != 3 {
+			continue
+		}
+		l = strings.Join(ff, " ")
+
+		v := strings.TrimSuffix(ff[1], "/go.mod")
+		if isDropped[ff[0]] && !required[module.Version{Path: ff[0], Version: v}] {
+			continue
+		}
+
+		lines[l] = true
+	}
+
+	for _, l := range sums {
+		lines[l] = true
+	}
+
+	sorted := make([]string, 0, len(lines))
+	for l := range lines {
+		sorted = append(sorted, l)
+	}
+	sortSums(sorted)
+
+	var buf bytes.Buffer
+	for _, l := range sorted {
+		buf.WriteString(l)
+		buf.WriteByte('\n')
+	}
+
+	return os.WriteFile(p, buf.Bytes(), 0o644)
+}
+
+// sortSums sorts the go.sum lines by the module path and version the way the
+// go command does.
+func sortSums(lines []string) {
+	sort.Slice(lines, func(i, j int) bool {
+		fi, fj := strings.Fields(lines[i]), strings.Fields(lines[j])
+		if fi[0] != fj[0] {
+			return fi[0] < fj[0]
+		}
+
+		return lessVersion(fi[1], fj[1])
+	})
+}
+
+// lessVersion compares the go.sum versions, which are optionally followed by
+// the "/go.mod" suffix.
+func lessVersion(v, w string) bool {
+	vv, vs, _ := strings.Cut(v, "/")
+	wv, ws, _ := strings.Cut(w, "/")
+	if c := semver.Compare(vv, wv); c != 0 {
+		return c < 0
+	}
+
+	return vs < ws
+}
+
+// cachedSums returns the go.sum hashes of the go.mod file and of the source
+// of the module available in the module cache, as well as the requirements of
+// the module. The hashes are empty if the files are missing from the cache.
+func cachedSums(cacheDir string, m module.Version) (modSum, zipSum string, reqs []module.Version, err error) {
+	dir, err := cacheVersionDir(cacheDir, m.Path)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	ev, err := module.EscapeVersion(m.Version)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	modPath := filepath.Join(dir, ev+".mod")
+	bb, err := os.ReadFile(modPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", "", nil, nil
+	}
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	modSum, err = dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(bb)), nil
+	})
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	if zs, err := os.ReadFile(filepath.Join(dir, ev+".ziphash")); err == nil {
+		zipSum = strings.TrimSpace(string(zs))
+	}
+
+	mf, err := modfile.ParseLax(modPath, bb, nil)
+	if err != nil {
+		return "", "", nil, err
+	}
+
+	for _, r := range mf.Require {
+		reqs = append(reqs, r.Mod)
+	}
+
+	return modSum, zipSum, reqs, nil
+}
+
+// cacheVersionDir returns the directory of the module cache holding the
+// downloaded versions of the module.
+func cacheVersionDir(cacheDir, modulePath string) (string, error) {
+	ep, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "cache", "download", filepath.FromSlash(ep), "@v"), nil
+}
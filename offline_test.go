@@ -0,0 +1,261 @@
+package gobump_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+func TestLatestCachedVersion(t *testing.T) {
+	cache := writeModule(t, map[string]string{
+		"cache/download/example.org/bar/v2/@v/list":              "v2.0.0\nv2.1.0\n",
+		"cache/download/example.org/bar/v2/@v/v2.0.0.mod":        "module example.org/bar/v2\n",
+		"cache/download/example.org/bar/v2/@v/v2.0.0.zip":        "",
+		"cache/download/example.org/bar/v2/@v/v2.1.0.mod":        "module example.org/bar/v2\n",
+		"cache/download/example.org/bar/v2/@v/v2.1.0.zip":        "",
+		"cache/download/example.org/bar/v2/@v/v2.2.0.mod":        "module example.org/bar/v2\n",
+		"cache/download/example.org/bar/v2/@v/v2.3.0-rc.1.mod":   "module example.org/bar/v2\n",
+		"cache/download/example.org/bar/v2/@v/v2.3.0-rc.1.zip":   "",
+		"cache/download/example.org/baz/v3/@v/v3.0.0-rc.1.mod":   "module example.org/baz/v3\n",
+		"cache/download/example.org/baz/v3/@v/v3.0.0-rc.1.zip":   "",
+		"cache/download/example.org/baz/v3/@v/v3.0.0-beta.1.mod": "module example.org/baz/v3\n",
+		"cache/download/example.org/baz/v3/@v/v3.0.0-beta.1.zip": "",
+		"cache/download/example.org/!big/v2/@v/v2.0.1.mod":       "module example.org/Big/v2\n",
+		"cache/download/example.org/!big/v2/@v/v2.0.1.zip":       "",
+	})
+
+	tests := []struct {
+		name       string
+		modulePath string
+		want       string
+	}{
+		{
+			name:       "should prefer the highest release with source",
+			modulePath: "example.org/bar/v2",
+			want:       "v2.1.0",
+		},
+		{
+			name:       "should fall back to the highest pre-release",
+			modulePath: "example.org/baz/v3",
+			want:       "v3.0.0-rc.1",
+		},
+		{
+			name:       "should resolve escaped paths",
+			modulePath: "example.org/Big/v2",
+			want:       "v2.0.1",
+		},
+		{
+			name:       "should return empty version for missing module",
+			modulePath: "example.org/qux/v2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LatestCachedVersion(cache, tt.modulePath)
+			if err != nil {
+				t.Fatalf("LatestCachedVersion() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("LatestCachedVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBump_offline(t *testing.T) {
+	barMod := "module example.org/bar/v2\n\ngo 1.20\n\nrequire example.org/qux v1.2.0\n"
+	quxMod := "module example.org/qux\n\ngo 1.20\n"
+
+	cache := writeModule(t, map[string]string{
+		"cache/download/example.org/bar/v2/@v/v2.1.0.mod":     barMod,
+		"cache/download/example.org/bar/v2/@v/v2.1.0.zip":     "",
+		"cache/download/example.org/bar/v2/@v/v2.1.0.ziphash": "h1:bar=\n",
+		"cache/download/example.org/qux/@v/v1.2.0.mod":        quxMod,
+	})
+
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"go.sum":  "example.org/bar v1.0.0 h1:old=\nexample.org/bar v1.0.0/go.mod h1:oldmod=\nexample.org/other v1.0.0/go.mod h1:other=\n",
+		"main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	runner := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go env GOMODCACHE GOFLAGS": {Output: cache + "\n-trimpath -mod=readonly\n"},
+		},
+	}
+	r, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   runner,
+		Env:      []string{"GOWORK=off"},
+		Offline:  true,
+	})
+	if err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar/v2 v2.1.0\n"; string(bb) != want {
+		t.Fatalf("Bump() wrote go.mod %q, want %q", bb, want)
+	}
+
+	wantSum := strings.Join([]string{
+		"example.org/bar/v2 v2.1.0 h1:bar=",
+		"example.org/bar/v2 v2.1.0/go.mod " + modHash(t, barMod),
+		"example.org/other v1.0.0/go.mod h1:other=",
+		"example.org/qux v1.2.0/go.mod " + modHash(t, quxMod),
+	}, "\n") + "\n"
+
+	bb, err = os.ReadFile(filepath.Join(dir, "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(bb) != wantSum {
+		t.Fatalf("Bump() wrote go.sum %q, want %q", bb, wantSum)
+	}
+
+	env := []string{"GOWORK=off", "GOFLAGS=-trimpath -mod=mod", "GOPROXY=off"}
+	want := []FakeRun{
+		{
+			Dir:  dir,
+			Env:  []string{"GOWORK=off"},
+			Args: []string{"go", "env", "GOMODCACHE", "GOFLAGS"},
+		},
+		{
+			Dir:  dir,
+			Env:  env,
+			Args: []string{"go", "mod", "tidy"},
+		},
+		{
+			Dir:  dir,
+			Env:  env,
+			Args: []string{"go", "mod", "graph"},
+		},
+	}
+	if got := runner.Runs(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("Bump() ran %+v, want %+v", got, want)
+	}
+
+	if want := []string{"main.go", "go.mod"}; !reflect.DeepEqual(r.ChangedFiles(), want) {
+		t.Fatalf("Bump() changed files = %v, want %v", r.ChangedFiles(), want)
+	}
+}
+
+func TestBump_offlineMissing(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go": "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+	})
+
+	runner := cacheRunner(t.TempDir())
+	_, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   runner,
+		Offline:  true,
+	})
+	if err == nil || !strings.Contains(err.Error(), "module example.org/bar/v2 is not found in the module cache") {
+		t.Fatalf("Bump() error = %v, want module not found error", err)
+	}
+
+	if n := len(runner.Runs()); n != 1 {
+		t.Fatalf("Bump() ran %d commands, want only 'go env'", n)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "main.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "package main\n\nimport _ \"example.org/bar/pkg\"\n"; string(bb) != want {
+		t.Fatalf("Bump() wrote %q, want the file left intact", bb)
+	}
+}
+
+func TestBump_offlineVendor(t *testing.T) {
+	dir := writeModule(t, map[string]string{
+		"go.mod":             "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar v1.0.0\n",
+		"main.go":            "package main\n\nimport _ \"example.org/bar/pkg\"\n",
+		"vendor/modules.txt": "# example.org/bar/v2 v2.0.3\n## explicit; go 1.20\nexample.org/bar/v2/pkg\n",
+	})
+
+	if _, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   cacheRunner(t.TempDir()),
+		Offline:  true,
+	}); err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(bb), "require example.org/bar/v2 v2.0.3\n") {
+		t.Fatalf("Bump() wrote go.mod %q, want the vendored version required", bb)
+	}
+}
+
+// cacheRunner returns a runner reporting the module cache located in dir.
+func cacheRunner(dir string) *FakeRunner {
+	return &FakeRunner{
+		Results: map[string]FakeResult{
+			"go env GOMODCACHE GOFLAGS": {Output: dir + "\n\n"},
+		},
+	}
+}
+
+// modHash returns the go.sum hash of the go.mod file content.
+func modHash(t *testing.T, content string) string {
+	t.Helper()
+
+	h, err := dirhash.Hash1([]string{"go.mod"}, func(string) (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader([]byte(content))), nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return h
+}
+
+func TestBump_offlineBothRequired(t *testing.T) {
+	cache := writeModule(t, map[string]string{
+		"cache/download/example.org/bar/v2/@v/v2.1.0.mod": "module example.org/bar/v2\n",
+		"cache/download/example.org/bar/v2/@v/v2.1.0.zip": "",
+	})
+
+	dir := writeModule(t, map[string]string{
+		"go.mod":  "module example.org/foo\n\ngo 1.20\n\nrequire (\n\texample.org/bar v1.0.0\n\texample.org/bar/v2 v2.0.0\n)\n",
+		"main.go": "package main\n\nimport (\n\t_ \"example.org/bar/pkg\"\n\t_ \"example.org/bar/v2/other\"\n)\n",
+	})
+
+	if _, err := Bump(context.Background(), Options{
+		Dir:      dir,
+		NewPaths: []string{"example.org/bar/v2"},
+		Runner:   cacheRunner(cache),
+		Offline:  true,
+	}); err != nil {
+		t.Fatalf("Bump() error = %v", err)
+	}
+
+	bb, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "module example.org/foo\n\ngo 1.20\n\nrequire example.org/bar/v2 v2.1.0\n"; string(bb) != want {
+		t.Fatalf("Bump() wrote go.mod %q, want %q", bb, want)
+	}
+}
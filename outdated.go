@@ -0,0 +1,186 @@
+package gobump
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
+	"golang.org/x/mod/semver"
+)
+
+// OutdatedModule is a direct dependency of a module along with the major
+// versions published after the required one.
+type OutdatedModule struct {
+	// Path is the required module path.
+	Path string `json:"path"`
+
+	// Major is the major version of the required module path, such as "v1".
+	Major string `json:"major"`
+
+	// Incompatible is true if the module is required at a "+incompatible"
+	// version, in which case Major is the major version of the required
+	// version rather than of the path.
+	Incompatible bool `json:"incompatible,omitempty"`
+
+	// Available is a list of the higher major versions, in ascending order.
+	Available []MajorVersion `json:"available"`
+
+	// Private is true if the module is private as per GONOPROXY or GOPRIVATE
+	// and is not checked.
+	Private bool `json:"private,omitempty"`
+
+	// Error is the error the module failed to be checked with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// MajorVersion is a published major version of a module.
+type MajorVersion struct {
+	// Path is the module path of the major version.
+	Path string `json:"path"`
+
+	// Major is the major version, such as "v2".
+	Major string `json:"major"`
+
+	// Latest is the latest published version of the major version.
+	Latest string `json:"latest"`
+}
+
+// Outdated returns the direct dependencies of the module located in
+// moduleDir along with the major versions published after the required ones.
+//
+// The errors of checking individual dependencies are recorded in the result
+// instead of failing the whole check.
+func Outdated(ctx context.Context, p *Proxy, moduleDir string) ([]OutdatedModule, error) {
+	mf, err := readModFile(os.DirFS(moduleDir), moduleDir)
+	if err != nil {
+		return nil, err
+	}
+
+	requires, versions := directRequires(mf), requiredVersions(mf)
+
+	res := []OutdatedModule{}
+	for _, r := range requires {
+		n, err := pathx.Major(r)
+		if err != nil {
+			return nil, err
+		}
+
+		v := versions[r]
+		incompatible := gomodfile.IsIncompatible(v)
+		if incompatible {
+			n, _ = strconv.Atoi(strings.TrimPrefix(semver.Major(v), "v"))
+		}
+
+		om := OutdatedModule{
+			Path:         r,
+			Major:        fmt.Sprintf("v%d", n),
+			Incompatible: incompatible,
+			Available:    []MajorVersion{},
+		}
+
+		if p.IsPrivate(r) {
+			om.Private = true
+		} else {
+			mm, err := higherMajors(ctx, p, r, n)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+				om.Error = err.Error()
+			}
+			om.Available = append(om.Available, mm...)
+		}
+
+		res = append(res, om)
+	}
+
+	return res, nil
+}
+
+// HigherMajors returns the major versions of the module published after the
+// major version of the given module path, in ascending order.
+//
+// The major versions are probed one by one until maxMajorGap consecutive ones
+// are not published, so a major version published after a longer gap is not
+// found.
+func HigherMajors(ctx context.Context, p *Proxy, modulePath string) ([]MajorVersion, error) {
+	major, err := pathx.Major(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	return higherMajors(ctx, p, modulePath, major)
+}
+
+// maxMajorGap is the number of consecutive major versions missing from the
+// proxies after which the higher major versions are no longer probed. It
+// allows finding the major versions published after a skipped one, such as v4
+// following v2.
+const maxMajorGap = 3
+
+// higherMajors returns the major versions of the module published after the
+// given major version.
+func higherMajors(
+	ctx context.Context,
+	p *Proxy,
+	modulePath string,
+	major int,
+) ([]MajorVersion, error) {
+	var res []MajorVersion
+	for n, missing := major+1, 0; missing < maxMajorGap; n++ {
+		mp, err := pathx.WithMajor(modulePath, n)
+		if err != nil {
+			return res, err
+		}
+
+		vv, err := p.Versions(ctx, mp)
+		if err != nil && !errors.Is(err, ErrModuleNotFound) {
+			return res, err
+		}
+
+		latest := latestVersion(vv, n)
+		if latest == "" {
+			missing++
+			continue
+		}
+		missing = 0
+
+		res = append(res, MajorVersion{
+			Path:   mp,
+			Major:  fmt.Sprintf("v%d", n),
+			Latest: latest,
+		})
+	}
+
+	return res, nil
+}
+
+// latestVersion returns the latest version of the given major version from
+// the list sorted in semver order, preferring releases over pre-releases.
+func latestVersion(vv []string, major int) string {
+	var latest, latestPre string
+
+	m := fmt.Sprintf("v%d", major)
+	for _, v := range vv {
+		if semver.Major(v) != m || semver.Build(v) != "" {
+			continue
+		}
+
+		if semver.Prerelease(v) == "" {
+			latest = v
+		} else {
+			latestPre = v
+		}
+	}
+
+	if latest == "" {
+		return latestPre
+	}
+
+	return latest
+}
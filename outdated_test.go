@@ -0,0 +1,91 @@
+package gobump_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestOutdated(t *testing.T) {
+	proxyURL := writeProxy(t, map[string]string{
+		"example.org/foo/v2":  "v2.0.0\nv2.1.0\n",
+		"example.org/foo/v3":  "v3.0.0-rc.1\n",
+		"example.org/foo/v6":  "v6.0.0\n",
+		"example.org/bar/v3":  "v3.0.0\n",
+		"gopkg.in/yaml.v3":    "v3.0.1\n",
+		"example.org/priv/v2": "v2.0.0\n",
+		"example.org/inc/v4":  "v4.0.0\n",
+	})
+
+	dir := t.TempDir()
+	gomod := "module example.org/app\n" +
+		"\n" +
+		"require (\n" +
+		"\texample.org/foo v1.0.0\n" +
+		"\texample.org/bar/v2 v2.0.0\n" +
+		"\tgopkg.in/yaml.v2 v2.4.0\n" +
+		"\texample.org/priv v1.0.0\n" +
+		"\texample.org/inc v3.2.1+incompatible\n" +
+		"\texample.org/other v1.0.0 // indirect\n" +
+		")\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(gomod), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := NewProxy(ProxyConfig{GOPROXY: proxyURL, GOPRIVATE: "example.org/priv"}, nil)
+	if err != nil {
+		t.Fatalf("NewProxy() error = %v", err)
+	}
+
+	got, err := Outdated(context.Background(), p, dir)
+	if err != nil {
+		t.Fatalf("Outdated() error = %v", err)
+	}
+
+	want := []OutdatedModule{
+		{
+			Path:  "example.org/foo",
+			Major: "v1",
+			Available: []MajorVersion{
+				{Path: "example.org/foo/v2", Major: "v2", Latest: "v2.1.0"},
+				{Path: "example.org/foo/v3", Major: "v3", Latest: "v3.0.0-rc.1"},
+				{Path: "example.org/foo/v6", Major: "v6", Latest: "v6.0.0"},
+			},
+		},
+		{
+			Path:  "example.org/bar/v2",
+			Major: "v2",
+			Available: []MajorVersion{
+				{Path: "example.org/bar/v3", Major: "v3", Latest: "v3.0.0"},
+			},
+		},
+		{
+			Path:  "gopkg.in/yaml.v2",
+			Major: "v2",
+			Available: []MajorVersion{
+				{Path: "gopkg.in/yaml.v3", Major: "v3", Latest: "v3.0.1"},
+			},
+		},
+		{
+			Path:      "example.org/priv",
+			Major:     "v1",
+			Available: []MajorVersion{},
+			Private:   true,
+		},
+		{
+			Path:         "example.org/inc",
+			Major:        "v3",
+			Incompatible: true,
+			Available: []MajorVersion{
+				{Path: "example.org/inc/v4", Major: "v4", Latest: "v4.0.0"},
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Outdated() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,248 @@
+package gobump
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// DefaultGOPROXY is the value of GOPROXY used by the go command if it is not
+// set.
+const DefaultGOPROXY = "https://proxy.golang.org,direct"
+
+// ErrModuleNotFound is returned if none of the proxies knows the module.
+var ErrModuleNotFound = errors.New("module not found")
+
+// ProxyConfig is the configuration of the Go module proxies, as described by
+// the environment variables of the go command.
+//
+// See https://go.dev/ref/mod#environment-variables.
+type ProxyConfig struct {
+	// GOPROXY is a list of the proxy URLs separated by commas or pipes. The
+	// "direct" and "off" keywords are supported. If empty, DefaultGOPROXY is
+	// used.
+	GOPROXY string
+
+	// GONOPROXY is a comma-separated list of the module path prefix patterns
+	// that are not fetched from the proxies. If empty, GOPRIVATE is used.
+	GONOPROXY string
+
+	// GOPRIVATE is a comma-separated list of the module path prefix patterns
+	// of the private modules.
+	GOPRIVATE string
+}
+
+// proxyEntry is an element of the GOPROXY list.
+type proxyEntry struct {
+	url string
+	// fallbackOnError is true if the next proxy is tried on any error, not
+	// only if the module is not found.
+	fallbackOnError bool
+}
+
+// Proxy is a client of the Go module proxy protocol. The "http", "https" and
+// "file" proxy URLs are supported.
+//
+// See https://go.dev/ref/mod#goproxy-protocol.
+type Proxy struct {
+	entries []proxyEntry
+	noProxy string
+	client  *http.Client
+}
+
+// ReadProxyConfig asks the go command run in Options.Dir for the proxy
+// configuration, including the settings made with 'go env -w'. The command is
+// run the same way as the go commands of Bump, with Options.Runner,
+// Options.Env and Options.CommandTimeout, and it is passed to
+// Options.OnCommand as an internal command.
+func ReadProxyConfig(ctx context.Context, opts Options) (ProxyConfig, error) {
+	if err := checkEnv(opts.Env); err != nil {
+		return ProxyConfig{}, err
+	}
+
+	out, err := runInternalCommand(
+		ctx,
+		opts,
+		NewReport(),
+		opts.Dir,
+		"go", "env", "-json", "GOPROXY", "GONOPROXY", "GOPRIVATE",
+	)
+	if err != nil {
+		return ProxyConfig{}, err
+	}
+
+	var vars map[string]string
+	if err := json.Unmarshal([]byte(out), &vars); err != nil {
+		return ProxyConfig{}, fmt.Errorf("invalid 'go env' output: %w", err)
+	}
+
+	return ProxyConfig{
+		GOPROXY:   vars["GOPROXY"],
+		GONOPROXY: vars["GONOPROXY"],
+		GOPRIVATE: vars["GOPRIVATE"],
+	}, nil
+}
+
+// NewProxy returns a new client of the proxies described by the config. If
+// client is nil, http.DefaultClient is used.
+func NewProxy(c ProxyConfig, client *http.Client) (*Proxy, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	list := c.GOPROXY
+	if list == "" {
+		list = DefaultGOPROXY
+	}
+
+	p := &Proxy{
+		noProxy: c.GONOPROXY,
+		client:  client,
+	}
+	if p.noProxy == "" {
+		p.noProxy = c.GOPRIVATE
+	}
+
+	for list != "" {
+		var (
+			e   proxyEntry
+			sep int
+		)
+
+		if sep = strings.IndexAny(list, ",|"); sep < 0 {
+			e.url, list = list, ""
+		} else {
+			e.url, e.fallbackOnError, list = list[:sep], list[sep] == '|', list[sep+1:]
+		}
+
+		e.url = strings.TrimSpace(e.url)
+		if e.url == "" {
+			continue
+		}
+
+		if e.url != "direct" && e.url != "off" {
+			u, err := url.Parse(e.url)
+			if err != nil {
+				return nil, fmt.Errorf("invalid GOPROXY URL %q: %w", e.url, err)
+			}
+
+			switch u.Scheme {
+			case "http", "https", "file":
+			default:
+				return nil, fmt.Errorf("unsupported GOPROXY URL %q", e.url)
+			}
+
+			e.url = strings.TrimSuffix(e.url, "/")
+		}
+
+		p.entries = append(p.entries, e)
+	}
+
+	return p, nil
+}
+
+// IsPrivate reports if the module is not fetched from the proxies as per
+// GONOPROXY or GOPRIVATE.
+func (p *Proxy) IsPrivate(modulePath string) bool {
+	return module.MatchPrefixPatterns(p.noProxy, modulePath)
+}
+
+// Versions returns the list of the known versions of the module, sorted in
+// semver order.
+//
+// The version control systems are not queried directly. So if the proxies
+// fall back to the "direct" mode or the module is private, ErrModuleNotFound
+// is returned.
+func (p *Proxy) Versions(ctx context.Context, modulePath string) ([]string, error) {
+	if p.IsPrivate(modulePath) {
+		return nil, ErrModuleNotFound
+	}
+
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, e := range p.entries {
+		switch e.url {
+		case "direct":
+			return nil, ErrModuleNotFound
+		case "off":
+			return nil, fmt.Errorf("module lookup disabled by GOPROXY=off")
+		}
+
+		bb, err := p.get(ctx, e.url+"/"+escaped+"/@v/list")
+		if err == nil {
+			return parseVersionList(bb), nil
+		}
+
+		if !errors.Is(err, ErrModuleNotFound) && !e.fallbackOnError {
+			return nil, err
+		}
+	}
+
+	return nil, ErrModuleNotFound
+}
+
+// get fetches the content at the given proxy URL. It returns
+// ErrModuleNotFound if the content does not exist.
+func (p *Proxy) get(ctx context.Context, rawURL string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.Scheme == "file" {
+		bb, err := os.ReadFile(filepath.FromSlash(u.Path))
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrModuleNotFound
+		}
+
+		return bb, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	switch res.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(res.Body)
+	case http.StatusNotFound, http.StatusGone:
+		return nil, ErrModuleNotFound
+	default:
+		return nil, fmt.Errorf("%s: unexpected status %s", rawURL, res.Status)
+	}
+}
+
+// parseVersionList parses the response of the "/@v/list" endpoint. The
+// invalid versions are omitted.
+func parseVersionList(bb []byte) []string {
+	var res []string
+	for _, v := range strings.Fields(string(bb)) {
+		if semver.IsValid(v) {
+			res = append(res, v)
+		}
+	}
+
+	semver.Sort(res)
+
+	return res
+}
@@ -0,0 +1,185 @@
+package gobump_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+// writeProxy writes a directory-based module proxy serving the given version
+// lists and returns its URL.
+func writeProxy(t *testing.T, lists map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for p, l := range lists {
+		f := filepath.Join(dir, filepath.FromSlash(p), "@v", "list")
+		if err := os.MkdirAll(filepath.Dir(f), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(f, []byte(l), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return "file://" + filepath.ToSlash(dir)
+}
+
+func TestProxy_Versions(t *testing.T) {
+	fileProxy := writeProxy(t, map[string]string{
+		"example.org/foo/v2":     "v2.1.0\nv2.0.0\ninvalid\n",
+		"example.org/!upper/bar": "v1.0.0\n",
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.org/baz/@v/list":
+			w.Write([]byte("v1.2.0\n"))
+		case "/example.org/broken/@v/list":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusGone)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		config     ProxyConfig
+		modulePath string
+		want       []string
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name:       "should read versions from a file proxy",
+			config:     ProxyConfig{GOPROXY: fileProxy},
+			modulePath: "example.org/foo/v2",
+			want:       []string{"v2.0.0", "v2.1.0"},
+		},
+		{
+			name:       "should escape upper-case module paths",
+			config:     ProxyConfig{GOPROXY: fileProxy},
+			modulePath: "example.org/Upper/bar",
+			want:       []string{"v1.0.0"},
+		},
+		{
+			name:       "should fall back to the next proxy if the module is not found",
+			config:     ProxyConfig{GOPROXY: fileProxy + "," + srv.URL},
+			modulePath: "example.org/baz",
+			want:       []string{"v1.2.0"},
+		},
+		{
+			name:       "should not fall back on errors after a comma",
+			config:     ProxyConfig{GOPROXY: srv.URL + "," + fileProxy},
+			modulePath: "example.org/broken",
+			wantAnyErr: true,
+		},
+		{
+			name:       "should fall back on errors after a pipe",
+			config:     ProxyConfig{GOPROXY: srv.URL + "|" + fileProxy},
+			modulePath: "example.org/broken",
+			wantErr:    ErrModuleNotFound,
+		},
+		{
+			name:       "should report missing modules when falling back to direct",
+			config:     ProxyConfig{GOPROXY: fileProxy + ",direct"},
+			modulePath: "example.org/foo/v3",
+			wantErr:    ErrModuleNotFound,
+		},
+		{
+			name:       "should fail if the proxies are off",
+			config:     ProxyConfig{GOPROXY: "off"},
+			modulePath: "example.org/foo/v2",
+			wantAnyErr: true,
+		},
+		{
+			name:       "should not query private modules",
+			config:     ProxyConfig{GOPROXY: fileProxy, GOPRIVATE: "example.org/foo"},
+			modulePath: "example.org/foo/v2",
+			wantErr:    ErrModuleNotFound,
+		},
+		{
+			name:       "should prefer GONOPROXY over GOPRIVATE",
+			config:     ProxyConfig{GOPROXY: fileProxy, GONOPROXY: "example.org/bar", GOPRIVATE: "example.org/foo"},
+			modulePath: "example.org/foo/v2",
+			want:       []string{"v2.0.0", "v2.1.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewProxy(tt.config, srv.Client())
+			if err != nil {
+				t.Fatalf("NewProxy() error = %v", err)
+			}
+
+			got, err := p.Versions(context.Background(), tt.modulePath)
+			if tt.wantAnyErr {
+				if err == nil {
+					t.Fatalf("Versions() error = nil, want an error")
+				}
+				return
+			}
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Versions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Versions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewProxy_InvalidURL(t *testing.T) {
+	if _, err := NewProxy(ProxyConfig{GOPROXY: "ftp://example.org"}, nil); err == nil {
+		t.Fatalf("NewProxy() error = nil, want an error")
+	}
+}
+
+func TestReadProxyConfig(t *testing.T) {
+	runner := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go env -json GOPROXY GONOPROXY GOPRIVATE": {
+				Output: `{"GOPROXY": "file:///tmp/proxy", "GONOPROXY": "", "GOPRIVATE": "example.org/private"}`,
+			},
+		},
+	}
+
+	var commands []Command
+	c, err := ReadProxyConfig(context.Background(), Options{
+		Dir:       "/src/foo",
+		Env:       []string{"GOFLAGS=-mod=mod"},
+		Runner:    runner,
+		OnCommand: func(c Command) { commands = append(commands, c) },
+	})
+	if err != nil {
+		t.Fatalf("ReadProxyConfig() error = %v", err)
+	}
+
+	want := ProxyConfig{GOPROXY: "file:///tmp/proxy", GOPRIVATE: "example.org/private"}
+	if c != want {
+		t.Fatalf("ReadProxyConfig() = %+v, want %+v", c, want)
+	}
+
+	wantRuns := []FakeRun{{
+		Dir:  "/src/foo",
+		Env:  []string{"GOFLAGS=-mod=mod"},
+		Args: []string{"go", "env", "-json", "GOPROXY", "GONOPROXY", "GOPRIVATE"},
+	}}
+	if !reflect.DeepEqual(runner.Runs(), wantRuns) {
+		t.Fatalf("ReadProxyConfig() ran %+v, want %+v", runner.Runs(), wantRuns)
+	}
+
+	if len(commands) != 1 || !commands[0].Internal {
+		t.Fatalf("ReadProxyConfig() reported commands %+v, want one internal command", commands)
+	}
+}
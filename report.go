@@ -0,0 +1,260 @@
+package gobump
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// Report is a machine-readable report of a module path update.
+//
+// It is safe for concurrent use.
+type Report struct {
+	m     sync.Mutex
+	index map[string]int
+
+	// NewPaths is a list of the new module paths.
+	NewPaths []string `json:"new_paths"`
+
+	// StartedAt is the time the update started at.
+	StartedAt time.Time `json:"started_at"`
+
+	// Duration is the duration of the update in nanoseconds.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Files is a list of the changed files.
+	Files []FileReport `json:"files"`
+
+	// Skipped is a list of the files left unchanged.
+	Skipped []SkippedFile `json:"skipped"`
+
+	// Failed is a list of the files that failed to be processed.
+	Failed []FailedFile `json:"failed,omitempty"`
+
+	// Commands is a list of the executed commands.
+	Commands []Command `json:"commands"`
+
+	// Notes is a list of the explanations of the non-trivial updates.
+	Notes []string `json:"notes,omitempty"`
+
+	// Warnings is a list of the problems found in the files that require
+	// attention.
+	Warnings []transformers.Warning `json:"warnings,omitempty"`
+
+	// Remaining is a list of the other major versions of the updated
+	// dependencies still required after the update.
+	Remaining []RemainingModule `json:"remaining,omitempty"`
+
+	// Verify is the report of building the updated modules. It is nil if the
+	// modules were not built.
+	Verify *VerifyReport `json:"verify,omitempty"`
+
+	// Error is the error the update failed with, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// FileReport is a report of the changes made to a file.
+type FileReport struct {
+	// Path is the slash-separated path of the file.
+	Path string `json:"path"`
+
+	// Changes is a list of the changes made to the file.
+	Changes []transformers.Change `json:"changes"`
+}
+
+// SkippedFile is a file left unchanged.
+type SkippedFile struct {
+	// Path is the slash-separated path of the file.
+	Path string `json:"path"`
+
+	// Reason is the reason the file is left unchanged.
+	Reason string `json:"reason"`
+}
+
+// FailedFile is a file that failed to be processed.
+type FailedFile struct {
+	// Path is the slash-separated path of the file.
+	Path string `json:"path"`
+
+	// Error is the error the file failed with.
+	Error string `json:"error"`
+}
+
+// The reasons of skipping files.
+const (
+	SkipNoTransformer = "no matching transformer"
+	SkipNoChanges     = "no changes"
+)
+
+// Command is a report of an executed command.
+type Command struct {
+	// Args is the command line.
+	Args []string `json:"args"`
+
+	// Dir is the working directory of the command.
+	Dir string `json:"dir,omitempty"`
+
+	// Env is the list of the environment variables the command is run with
+	// in addition to the environment of the current process.
+	Env []string `json:"env,omitempty"`
+
+	// ExitCode is the exit code of the command, or -1 if the command could not
+	// be started or timed out.
+	ExitCode int `json:"exit_code"`
+
+	// Output is the combined standard output and standard error of the
+	// command.
+	Output string `json:"output"`
+
+	// Duration is the duration of the command in nanoseconds.
+	Duration time.Duration `json:"duration_ns"`
+
+	// Internal is true if the command is run to inspect the module rather
+	// than to update it, such as 'go mod graph', and its output is
+	// summarised elsewhere in the report.
+	Internal bool `json:"internal,omitempty"`
+}
+
+// NewReport returns a new report of the module path update to the given paths
+// started now.
+func NewReport(newPaths ...string) *Report {
+	if newPaths == nil {
+		newPaths = []string{}
+	}
+
+	return &Report{
+		index:     map[string]int{},
+		NewPaths:  newPaths,
+		StartedAt: time.Now(),
+		Files:     []FileReport{},
+		Skipped:   []SkippedFile{},
+		Commands:  []Command{},
+	}
+}
+
+// AddChange records a change made to a file.
+func (r *Report) AddChange(c transformers.Change) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	i, ok := r.index[c.Path]
+	if !ok {
+		i = len(r.Files)
+		r.index[c.Path] = i
+		r.Files = append(r.Files, FileReport{Path: c.Path})
+	}
+
+	r.Files[i].Changes = append(r.Files[i].Changes, c)
+}
+
+// ChangedFiles returns the paths of the changed files.
+func (r *Report) ChangedFiles() []string {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	res := make([]string, 0, len(r.Files))
+	for _, f := range r.Files {
+		res = append(res, f.Path)
+	}
+
+	return res
+}
+
+// AddSkipped records a file left unchanged.
+func (r *Report) AddSkipped(path, reason string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Skipped = append(r.Skipped, SkippedFile{Path: path, Reason: reason})
+}
+
+// AddFailed records a file that failed to be processed.
+func (r *Report) AddFailed(path string, err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Failed = append(r.Failed, FailedFile{Path: path, Error: err.Error()})
+}
+
+// FailedFiles returns the files that failed to be processed.
+func (r *Report) FailedFiles() []FailedFile {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]FailedFile(nil), r.Failed...)
+}
+
+// AddCommand records an executed command.
+func (r *Report) AddCommand(c Command) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Commands = append(r.Commands, c)
+}
+
+// ExecutedCommands returns the executed commands.
+func (r *Report) ExecutedCommands() []Command {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]Command(nil), r.Commands...)
+}
+
+// AddNote records an explanation of a non-trivial update.
+func (r *Report) AddNote(note string) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Notes = append(r.Notes, note)
+}
+
+// AddWarning records a problem found in a file.
+func (r *Report) AddWarning(w transformers.Warning) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Warnings = append(r.Warnings, w)
+}
+
+// AddRemaining records another major version of an updated dependency still
+// required after the update.
+func (r *Report) AddRemaining(m RemainingModule) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Remaining = append(r.Remaining, m)
+}
+
+// SetVerify records the report of building the updated modules.
+func (r *Report) SetVerify(v *VerifyReport) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Verify = v
+}
+
+// Finish records the duration of the update and the error it failed with, if
+// any.
+func (r *Report) Finish(err error) {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	r.Duration = time.Since(r.StartedAt)
+	if err != nil {
+		r.Error = err.Error()
+	}
+}
+
+// WriteJSON writes the report to w in JSON format.
+func (r *Report) WriteJSON(w io.Writer) error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(r)
+}
@@ -0,0 +1,88 @@
+package gobump_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestReport(t *testing.T) {
+	r := NewReport("example.org/foo/v2", "example.org/bar/v3")
+
+	r.AddChange(transformers.Change{Path: "a.go", Line: 3, Column: 8, Old: "example.org/foo", New: "example.org/foo/v2"})
+	r.AddSkipped("b.go", SkipNoChanges)
+	r.AddFailed("c.go", errors.New("<parse error>"))
+	r.AddChange(transformers.Change{Path: "go.mod", Line: 1, Column: 8, Old: "example.org/foo", New: "example.org/foo/v2"})
+	r.AddChange(transformers.Change{Path: "a.go", Line: 4, Column: 8, Old: "example.org/foo/bar", New: "example.org/foo/v2/bar"})
+	r.AddWarning(transformers.Warning{Path: "a.go", Message: "<warning>"})
+	r.AddCommand(Command{Args: []string{"go", "mod", "tidy"}, ExitCode: 1, Output: "<output>"})
+	r.Finish(errors.New("<error>"))
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatalf("WriteJSON() error = %v", err)
+	}
+
+	var got struct {
+		NewPaths []string `json:"new_paths"`
+		Files    []struct {
+			Path    string `json:"path"`
+			Changes []struct {
+				Line int    `json:"line"`
+				New  string `json:"new"`
+			} `json:"changes"`
+		} `json:"files"`
+		Skipped  []SkippedFile          `json:"skipped"`
+		Failed   []FailedFile           `json:"failed"`
+		Warnings []transformers.Warning `json:"warnings"`
+		Commands []struct {
+			Args     []string `json:"args"`
+			ExitCode int      `json:"exit_code"`
+		} `json:"commands"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("WriteJSON() wrote invalid JSON: %v", err)
+	}
+
+	if want := []string{"example.org/foo/v2", "example.org/bar/v3"}; !reflect.DeepEqual(got.NewPaths, want) {
+		t.Errorf("WriteJSON() new_paths = %v, want %v", got.NewPaths, want)
+	}
+
+	if got.Error != "<error>" {
+		t.Errorf("WriteJSON() error = %q", got.Error)
+	}
+
+	var files []string
+	for _, f := range got.Files {
+		files = append(files, f.Path)
+	}
+	if want := []string{"a.go", "go.mod"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("WriteJSON() files = %v, want %v", files, want)
+	}
+
+	if n := len(got.Files[0].Changes); n != 2 {
+		t.Errorf("WriteJSON() recorded %d changes of a.go, want 2", n)
+	}
+
+	if want := []SkippedFile{{Path: "b.go", Reason: SkipNoChanges}}; !reflect.DeepEqual(got.Skipped, want) {
+		t.Errorf("WriteJSON() skipped = %v, want %v", got.Skipped, want)
+	}
+
+	if want := []FailedFile{{Path: "c.go", Error: "<parse error>"}}; !reflect.DeepEqual(got.Failed, want) {
+		t.Errorf("WriteJSON() failed = %v, want %v", got.Failed, want)
+	}
+
+	if want := []transformers.Warning{{Path: "a.go", Message: "<warning>"}}; !reflect.DeepEqual(got.Warnings, want) {
+		t.Errorf("WriteJSON() warnings = %v, want %v", got.Warnings, want)
+	}
+
+	if len(got.Commands) != 1 || got.Commands[0].ExitCode != 1 {
+		t.Errorf("WriteJSON() commands = %+v", got.Commands)
+	}
+}
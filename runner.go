@@ -0,0 +1,110 @@
+package gobump
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+)
+
+// Runner runs the commands, such as 'go get', on behalf of Bump.
+type Runner interface {
+	// Run runs the command in the directory and returns its combined
+	// standard output and standard error.
+	//
+	// The env is a list of the environment variables in the "KEY=value"
+	// form overriding the environment of the current process.
+	//
+	// If the command runs but fails, the returned error must have the
+	// ExitCode() int method, as *exec.ExitError does. Any other error means
+	// the command could not be run.
+	Run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error)
+}
+
+// ExecRunner is a Runner that runs the commands as the processes of the
+// operating system. The commands are killed once the context is done.
+type ExecRunner struct{}
+
+// Run runs the command.
+func (ExecRunner) Run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
+
+	return cmd.CombinedOutput()
+}
+
+// FakeRunner is a Runner that does not run the commands, for use in tests.
+// It records the commands and returns the results configured for them.
+//
+// It is safe for concurrent use.
+type FakeRunner struct {
+	// Results maps the command lines, such as "go mod tidy", to the results
+	// of the commands. The other commands succeed with no output.
+	Results map[string]FakeResult
+
+	m    sync.Mutex
+	runs []FakeRun
+}
+
+// FakeResult is the result of a command run by FakeRunner.
+type FakeResult struct {
+	// Output is the output of the command.
+	Output string
+
+	// ExitCode is the exit code of the command. If it is not zero, the
+	// command fails.
+	ExitCode int
+}
+
+// FakeRun is a record of a command run by FakeRunner.
+type FakeRun struct {
+	Dir  string
+	Env  []string
+	Args []string
+}
+
+// Run records the command and returns the configured result.
+func (r *FakeRunner) Run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	r.m.Lock()
+	r.runs = append(r.runs, FakeRun{
+		Dir:  dir,
+		Env:  append([]string(nil), env...),
+		Args: append([]string(nil), args...),
+	})
+	r.m.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	res := r.Results[strings.Join(args, " ")]
+	if res.ExitCode != 0 {
+		return []byte(res.Output), fakeExitError(res.ExitCode)
+	}
+
+	return []byte(res.Output), nil
+}
+
+// Runs returns the commands run so far.
+func (r *FakeRunner) Runs() []FakeRun {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return append([]FakeRun(nil), r.runs...)
+}
+
+// fakeExitError is the error of a command failed in FakeRunner.
+type fakeExitError int
+
+func (e fakeExitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e fakeExitError) ExitCode() int {
+	return int(e)
+}
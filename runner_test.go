@@ -0,0 +1,55 @@
+package gobump_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestExecRunner(t *testing.T) {
+	dir := t.TempDir()
+
+	out, err := ExecRunner{}.Run(context.Background(), dir, []string{"GOFLAGS=-mod=mod"}, "go", "env", "GOFLAGS")
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "-mod=mod" {
+		t.Fatalf("Run() output = %q, want %q", got, "-mod=mod")
+	}
+
+	_, err = ExecRunner{}.Run(context.Background(), dir, nil, "go", "no-such-command")
+
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() == 0 {
+		t.Fatalf("Run() error = %v, want exit error", err)
+	}
+}
+
+func TestFakeRunner(t *testing.T) {
+	r := &FakeRunner{
+		Results: map[string]FakeResult{
+			"go build ./...": {Output: "main.go:1:1: error\n", ExitCode: 2},
+		},
+	}
+
+	out, err := r.Run(context.Background(), "dir", nil, "go", "build", "./...")
+
+	var exitErr interface{ ExitCode() int }
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Fatalf("Run() error = %v, want exit status 2", err)
+	}
+	if string(out) != "main.go:1:1: error\n" {
+		t.Fatalf("Run() output = %q, want the configured output", out)
+	}
+
+	if out, err := r.Run(context.Background(), "dir", nil, "go", "vet", "./..."); err != nil || len(out) != 0 {
+		t.Fatalf("Run() = %q, %v, want no output and no error", out, err)
+	}
+
+	if n := len(r.Runs()); n != 2 {
+		t.Fatalf("Runs() returned %d commands, want 2", n)
+	}
+}
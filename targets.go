@@ -0,0 +1,32 @@
+package gobump
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ReadTargets reads the list of the new module paths from r. The paths are
+// separated by whitespace, empty lines and the text after '#' are ignored.
+//
+// An example of the list:
+//
+//	# weekly dependency update
+//	github.com/exampleorg/foo/v3
+//	github.com/exampleorg/bar/v2 # breaking changes in the client
+func ReadTargets(r io.Reader) ([]string, error) {
+	var res []string
+
+	s := bufio.NewScanner(r)
+	for s.Scan() {
+		l, _, _ := strings.Cut(s.Text(), "#")
+		res = append(res, strings.Fields(l)...)
+	}
+
+	if err := s.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read module paths: %w", err)
+	}
+
+	return res, nil
+}
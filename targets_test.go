@@ -0,0 +1,32 @@
+package gobump_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestReadTargets(t *testing.T) {
+	in := "# weekly update\n" +
+		"\n" +
+		"example.org/foo/v3\n" +
+		"  example.org/bar/v2 # breaking changes\n" +
+		"example.org/baz/v4 example.org/qux/v2\n"
+
+	got, err := ReadTargets(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("ReadTargets() error = %v", err)
+	}
+
+	want := []string{
+		"example.org/foo/v3",
+		"example.org/bar/v2",
+		"example.org/baz/v4",
+		"example.org/qux/v2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ReadTargets() = %v, want %v", got, want)
+	}
+}
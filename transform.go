@@ -0,0 +1,65 @@
+package gobump
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// TransformFile runs the registered transformers against the file of fsys
+// described by the context. Every transformer updates all the new module paths
+// in a single pass over the file. If transformers performed conflicting
+// changes to the file, the last transformer always takes precedence.
+//
+// The file is read and written at most once regardless of the number of the
+// module paths and transformers.
+//
+// It returns true if any of the transformers changed the file.
+func TransformFile(
+	fsys WriteFS,
+	ctx transformers.Context,
+	newPaths []string,
+	regs ...transformers.Registration,
+) (bool, error) {
+	if len(regs) == 0 || len(newPaths) == 0 {
+		return false, nil
+	}
+
+	content, err := fs.ReadFile(fsys, ctx.Path)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		buf     bytes.Buffer
+		changed bool
+	)
+	for _, r := range regs {
+		c := ctx
+		c.Transformer = r.Name
+
+		buf.Reset()
+		ok, err := r.New(newPaths).TransformFile(&c, bytes.NewReader(content), &buf)
+		if err != nil {
+			return false, fmt.Errorf("%s transformer: %w", r.Name, err)
+		}
+		if !ok {
+			continue
+		}
+
+		changed = true
+		content = bytes.Clone(buf.Bytes())
+	}
+
+	if !changed {
+		return false, nil
+	}
+
+	if err := fsys.WriteFile(ctx.Path, content, 0o644); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,62 @@
+package gobump_test
+
+import (
+	"io"
+	"io/fs"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestTransformFile(t *testing.T) {
+	fsys := NewMemFS(map[string][]byte{
+		"a.txt": []byte("paths:"),
+	})
+
+	// appendPaths appends the new paths to the file.
+	var calls int
+	appendPaths := transformers.Registration{
+		Name: "append",
+		New: func(pp []string) transformers.FileTransformer {
+			return transformers.Func(func(_ *transformers.Context, in io.Reader, out io.Writer) (bool, error) {
+				calls++
+
+				if _, err := io.Copy(out, in); err != nil {
+					return false, err
+				}
+
+				_, err := io.WriteString(out, " "+strings.Join(pp, " "))
+				return true, err
+			})
+		},
+	}
+
+	ok, err := TransformFile(
+		fsys,
+		transformers.Context{Path: "a.txt"},
+		[]string{"example.org/foo/v2", "example.org/bar/v2"},
+		appendPaths,
+	)
+	if err != nil {
+		t.Fatalf("TransformFile() error = %v", err)
+	}
+
+	if !ok {
+		t.Fatal("TransformFile() ok = false, want true")
+	}
+
+	if calls != 1 {
+		t.Fatalf("TransformFile() ran the transformer %d times, want 1", calls)
+	}
+
+	bb, err := fs.ReadFile(fsys, "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "paths: example.org/foo/v2 example.org/bar/v2"; string(bb) != want {
+		t.Fatalf("TransformFile() wrote %q, want %q", bb, want)
+	}
+}
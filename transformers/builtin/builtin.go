@@ -0,0 +1,115 @@
+// Package builtin registers the transformers shipped with gobump.
+package builtin
+
+import (
+	"regexp"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	"github.com/danilvpetrov/gobump/transformers/codegenfile"
+	"github.com/danilvpetrov/gobump/transformers/gofile"
+	"github.com/danilvpetrov/gobump/transformers/gomodfile"
+	"github.com/danilvpetrov/gobump/transformers/gotmplfile"
+	"github.com/danilvpetrov/gobump/transformers/mdfile"
+	"github.com/danilvpetrov/gobump/transformers/protofile"
+)
+
+// Config is the configuration of the built-in transformers.
+type Config struct {
+	// MarkdownSkipSections is a list of Markdown section headings that are
+	// left intact.
+	MarkdownSkipSections []string
+}
+
+// oapiCodegenContent matches the content of oapi-codegen configuration files
+// that have no conventional name.
+var oapiCodegenContent = regexp.MustCompile(`(?m)^\s*"?import-mapping"?\s*:`)
+
+// NewRegistry returns a new registry with the built-in transformers.
+func NewRegistry(c Config) *transformers.Registry {
+	r := transformers.NewRegistry()
+	if err := Register(r, c); err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// Register adds the built-in transformers to the registry.
+func Register(r *transformers.Registry, c Config) error {
+	for _, reg := range []transformers.Registration{
+		{
+			Name:        "gomod",
+			Description: "module directive in go.mod files",
+			Match:       transformers.MatchBase("go.mod"),
+			New:         transformers.FuncFactory(gomodfile.UpdateModulePathFunc),
+		},
+		{
+			Name:        "gomod-incompatible",
+			Description: "+incompatible requirements of modules adopting go.mod at v2+ in go.mod files",
+			Match:       transformers.MatchBase("go.mod"),
+			New:         transformers.FuncFactory(gomodfile.DropIncompatible),
+		},
+		{
+			Name:        "go-imports",
+			Description: "import declarations in .go files",
+			Match:       transformers.MatchExt(".go"),
+			New:         transformers.FuncFactory(gofile.UpdateImportsFunc),
+		},
+		{
+			Name:        "go-mockgen",
+			Description: "mockgen //go:generate directives in .go files",
+			Match:       transformers.MatchExt(".go"),
+			New:         transformers.FuncFactory(gofile.UpdateMockgenDirectives),
+		},
+		{
+			Name:        "go-template",
+			Description: "quoted import paths in Go source templates (*.go.tmpl, *.gotmpl, *.go.tpl)",
+			Match:       transformers.MatchGlob("*.go.tmpl", "*.gotmpl", "*.go.tpl"),
+			New:         transformers.FuncFactory(gotmplfile.UpdateImports),
+		},
+		{
+			Name:        "proto",
+			Description: "imports and go_package options in .proto files",
+			Match:       transformers.MatchExt(".proto"),
+			New:         transformers.FuncFactory(protofile.UpdateModulePathFunc),
+		},
+		{
+			Name:        "markdown",
+			Description: "go commands, documentation links and Go code blocks in .md files",
+			Match:       transformers.MatchExt(".md"),
+			New: func(modulePaths []string) transformers.FileTransformer {
+				return mdfile.UpdateModulePath(modulePaths, c.MarkdownSkipSections...)
+			},
+		},
+		{
+			Name:        "gqlgen",
+			Description: "autobind packages and model types in gqlgen configuration",
+			Match:       transformers.MatchBase("gqlgen.yml", "gqlgen.yaml", ".gqlgen.yml"),
+			New:         transformers.FuncFactory(codegenfile.UpdateGqlgenConfig),
+		},
+		{
+			Name:        "sqlc",
+			Description: "go_type overrides in sqlc configuration",
+			Match:       transformers.MatchBase("sqlc.yaml", "sqlc.yml", "sqlc.json"),
+			New:         transformers.FuncFactory(codegenfile.UpdateSqlcConfig),
+		},
+		{
+			Name:        "oapi-codegen",
+			Description: "import mapping and additional imports in oapi-codegen configuration",
+			Match: transformers.MatchAll(
+				transformers.MatchExt(".yaml", ".yml", ".json"),
+				transformers.MatchAny(
+					transformers.MatchGlob("*oapi*"),
+					transformers.MatchContent(oapiCodegenContent),
+				),
+			),
+			New: transformers.FuncFactory(codegenfile.UpdateOapiCodegenConfig),
+		},
+	} {
+		if err := r.Register(reg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package codegenfile
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// valueKind is a kind of the configuration value holding a Go package
+// reference.
+type valueKind int
+
+const (
+	// noValue is a value that does not hold a package reference.
+	noValue valueKind = iota
+	// packageValue is a value holding a package import path, such as
+	// "example.org/foo/bar".
+	packageValue
+	// typeValue is a value holding a package-qualified type name, such as
+	// "example.org/foo/bar.Type".
+	typeValue
+)
+
+// rule describes a configuration value holding a Go package reference.
+//
+// The path is a dot-separated list of keys leading to the value. The "*"
+// element matches any single key, the "**" element matches any number of
+// keys and the "[]" element matches a list item.
+type rule struct {
+	path string
+	kind valueKind
+}
+
+// schema is a set of rules describing a well-known configuration file.
+type schema []rule
+
+// kindOf returns the kind of the value located at the given key path.
+func (s schema) kindOf(keys []string) valueKind {
+	for _, r := range s {
+		if matchKeys(strings.Split(r.path, "."), keys) {
+			return r.kind
+		}
+	}
+
+	return noValue
+}
+
+// matchKeys reports if the key path matches the pattern.
+func matchKeys(pattern, keys []string) bool {
+	if len(pattern) == 0 {
+		return len(keys) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(keys); i++ {
+			if matchKeys(pattern[1:], keys[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(keys) == 0 {
+		return false
+	}
+
+	if pattern[0] != "*" && pattern[0] != keys[0] {
+		return false
+	}
+
+	return matchKeys(pattern[1:], keys[1:])
+}
+
+// rewriteFunc rewrites a configuration value located at the given key path.
+// If the value is not rewritten, ok is returned as false.
+type rewriteFunc func(keys []string, value string) (_ string, ok bool, _ error)
+
+// reportFunc reports a value replaced at the given 1-based position.
+type reportFunc func(line, column int, oldValue, newValue string)
+
+// updateConfig returns a transformer updating the Go package references
+// described by the schema in a YAML or JSON configuration file.
+func updateConfig(
+	modulePaths []string,
+	s schema,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		if _, err := pathx.ModulePrefixes(modulePaths); err != nil {
+			return false, err
+		}
+
+		bb, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		modules := ctx.KnownModules()
+		rewrite := func(keys []string, v string) (string, bool, error) {
+			return updateValue(modulePaths, modules, s.kindOf(keys), v)
+		}
+
+		var res []byte
+		if isJSON(bb) {
+			res, ok, err = rewriteJSON(bb, rewrite, ctx.Report)
+		} else {
+			res, ok, err = rewriteYAML(bb, rewrite, ctx.Report)
+		}
+		if err != nil || !ok {
+			return false, err
+		}
+
+		if _, err := out.Write(res); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// updateValue updates the Go package reference in the configuration value of
+// the given kind. The modules are the paths of the modules known to own
+// packages.
+func updateValue(
+	modulePaths []string,
+	modules []string,
+	k valueKind,
+	v string,
+) (_ string, ok bool, _ error) {
+	switch k {
+	case packageValue:
+		return pathx.UpdateImportPathAny(modulePaths, v, modules...)
+	case typeValue:
+		i := strings.LastIndex(v, "/")
+		if i < 0 {
+			return "", false, nil
+		}
+
+		j := strings.LastIndex(v[i:], ".")
+		if j < 0 {
+			return "", false, nil
+		}
+
+		p, typ := v[:i+j], v[i+j:]
+
+		np, ok, err := pathx.UpdateImportPathAny(modulePaths, p, modules...)
+		if err != nil || !ok {
+			return "", false, err
+		}
+
+		return np + typ, true, nil
+	default:
+		return "", false, nil
+	}
+}
+
+// edit is a replacement of the [start, end) range of a text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// applyEdits applies non-overlapping edits ordered by their position to the
+// text.
+func applyEdits(s string, ee []edit) string {
+	var (
+		b    strings.Builder
+		last int
+	)
+
+	for _, e := range ee {
+		b.WriteString(s[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// isJSON reports if the content looks like a JSON document.
+func isJSON(bb []byte) bool {
+	bb = bytes.TrimSpace(bb)
+	return len(bb) > 0 && bb[0] == '{'
+}
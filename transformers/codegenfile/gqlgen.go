@@ -0,0 +1,24 @@
+package codegenfile
+
+import "github.com/danilvpetrov/gobump/transformers"
+
+// gqlgenSchema describes the package references in gqlgen configuration.
+//
+// See https://gqlgen.com/config/ for reference.
+var gqlgenSchema = schema{
+	{path: "autobind", kind: packageValue},
+	{path: "autobind.[]", kind: packageValue},
+	{path: "models.*.model", kind: typeValue},
+	{path: "models.*.model.[]", kind: typeValue},
+}
+
+// UpdateGqlgenConfig updates the Go module paths in gqlgen configuration file,
+// such as gqlgen.yml.
+//
+// It updates the packages listed in 'autobind' and the types referenced by
+// 'models.*.model'.
+func UpdateGqlgenConfig(
+	modulePaths ...string,
+) transformers.Func {
+	return updateConfig(modulePaths, gqlgenSchema)
+}
@@ -0,0 +1,114 @@
+package codegenfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/codegenfile"
+)
+
+func TestUpdateGqlgenConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		config     string
+		wantOut    string
+		wantOk     bool
+		wantErr    bool
+	}{
+		{
+			name:       "should update autobind packages and model types to a newer version",
+			modulePath: "example.org/foo/bar/v2",
+			config: `schema:
+  - graph/*.graphqls
+
+# Packages to bind the types from.
+autobind:
+- "example.org/foo/bar/graph/model"
+- example.org/baz/model
+
+models:
+  ID:
+    model:
+      - github.com/99designs/gqlgen/graphql.ID
+      - example.org/foo/bar/graph/scalar.ID # custom ID
+  User:
+    model: example.org/foo/bar/graph/model.User
+    fields:
+      friends:
+        resolver: true
+  Todo:
+    model: [example.org/foo/bar/todo.Todo, 'example.org/foo/bar/todo.Item']
+`,
+			wantOut: `schema:
+  - graph/*.graphqls
+
+# Packages to bind the types from.
+autobind:
+- "example.org/foo/bar/v2/graph/model"
+- example.org/baz/model
+
+models:
+  ID:
+    model:
+      - github.com/99designs/gqlgen/graphql.ID
+      - example.org/foo/bar/v2/graph/scalar.ID # custom ID
+  User:
+    model: example.org/foo/bar/v2/graph/model.User
+    fields:
+      friends:
+        resolver: true
+  Todo:
+    model: [example.org/foo/bar/v2/todo.Todo, 'example.org/foo/bar/v2/todo.Item']
+`,
+			wantOk: true,
+		},
+		{
+			name:       "should update model types to an older version",
+			modulePath: "example.org/foo/bar",
+			config: `models:
+  User:
+    model: example.org/foo/bar/v2/graph/model.User
+`,
+			wantOut: `models:
+  User:
+    model: example.org/foo/bar/graph/model.User
+`,
+			wantOk: true,
+		},
+		{
+			name:       "should not update values outside of the known keys",
+			modulePath: "example.org/foo/bar/v2",
+			config: `exec:
+  filename: example.org/foo/bar/generated.go
+description: |
+  model: example.org/foo/bar/graph/model.User
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should return an error if the module path is invalid",
+			modulePath: "example.org/foo/bar/v1",
+			config:     "autobind: example.org/foo/bar/graph/model\n",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.config), &bytes.Buffer{}
+
+			ok, err := UpdateGqlgenConfig(tt.modulePath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateGqlgenConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateGqlgenConfig() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateGqlgenConfig() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
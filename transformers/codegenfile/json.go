@@ -0,0 +1,135 @@
+package codegenfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// jsonFrame is a JSON object or array being decoded.
+type jsonFrame struct {
+	isArray   bool
+	key       string
+	expectKey bool
+}
+
+// rewriteJSON rewrites the string values of a JSON document using the rewrite
+// function. The document layout is preserved.
+func rewriteJSON(
+	in []byte,
+	rewrite rewriteFunc,
+	report reportFunc,
+) (_ []byte, ok bool, _ error) {
+	var (
+		ee    []edit
+		stack []*jsonFrame
+		prev  int
+	)
+
+	dec := json.NewDecoder(bytes.NewReader(in))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+
+		end := int(dec.InputOffset())
+		start := prev
+		prev = end
+
+		var top *jsonFrame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '{', '[':
+				stack = append(stack, &jsonFrame{isArray: d == '[', expectKey: d == '{'})
+			case '}', ']':
+				stack = stack[:len(stack)-1]
+				if len(stack) > 0 {
+					stack[len(stack)-1].expectKey = !stack[len(stack)-1].isArray
+				}
+			}
+			continue
+		}
+
+		if top != nil && top.expectKey {
+			top.key, top.expectKey = tok.(string), false
+			continue
+		}
+
+		if top != nil && !top.isArray {
+			top.expectKey = true
+		}
+
+		s, isString := tok.(string)
+		if !isString {
+			continue
+		}
+
+		keys := jsonKeys(stack)
+		nv, ok, err := rewrite(keys, s)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+
+		// The decoder offsets point to the end of the tokens, so the string
+		// literal starts at the first quote after the previous token.
+		start += bytes.IndexByte(in[start:end], '"')
+
+		nb, err := marshalJSONString(nv)
+		if err != nil {
+			return nil, false, err
+		}
+
+		ee = append(ee, edit{start: start, end: end, text: string(nb)})
+
+		line := bytes.Count(in[:start], []byte("\n")) + 1
+		column := start - bytes.LastIndexByte(in[:start], '\n') + 1
+		report(line, column, s, nv)
+	}
+
+	if len(ee) == 0 {
+		return nil, false, nil
+	}
+
+	return []byte(applyEdits(string(in), ee)), true, nil
+}
+
+// jsonKeys returns the key path of the value being decoded.
+func jsonKeys(stack []*jsonFrame) []string {
+	keys := make([]string, 0, len(stack))
+	for _, f := range stack {
+		if f.isArray {
+			keys = append(keys, "[]")
+		} else {
+			keys = append(keys, f.key)
+		}
+	}
+
+	return keys
+}
+
+// marshalJSONString marshals the string as a JSON string literal without
+// escaping HTML characters.
+func marshalJSONString(s string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,23 @@
+package codegenfile
+
+import "github.com/danilvpetrov/gobump/transformers"
+
+// oapiCodegenSchema describes the package references in oapi-codegen
+// configuration.
+//
+// See https://github.com/oapi-codegen/oapi-codegen#usage for reference.
+var oapiCodegenSchema = schema{
+	{path: "import-mapping.*", kind: packageValue},
+	{path: "output-options.additional-imports.[].package", kind: packageValue},
+}
+
+// UpdateOapiCodegenConfig updates the Go module paths in oapi-codegen
+// configuration file.
+//
+// It updates the packages listed in 'import-mapping' and
+// 'output-options.additional-imports'.
+func UpdateOapiCodegenConfig(
+	modulePaths ...string,
+) transformers.Func {
+	return updateConfig(modulePaths, oapiCodegenSchema)
+}
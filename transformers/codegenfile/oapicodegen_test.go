@@ -0,0 +1,78 @@
+package codegenfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/codegenfile"
+)
+
+func TestUpdateOapiCodegenConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		config     string
+		wantOut    string
+		wantOk     bool
+		wantErr    bool
+	}{
+		{
+			name:       "should update import mapping and additional imports",
+			modulePath: "example.org/foo/bar/v3",
+			config: `package: api
+output: api.gen.go
+import-mapping:
+  ./common.yaml: example.org/foo/bar/v2/api/common
+  "../other.yaml": "example.org/other/api"
+output-options:
+  additional-imports:
+    - package: example.org/foo/bar/v2/api/ext
+      alias: ext
+`,
+			wantOut: `package: api
+output: api.gen.go
+import-mapping:
+  ./common.yaml: example.org/foo/bar/v3/api/common
+  "../other.yaml": "example.org/other/api"
+output-options:
+  additional-imports:
+    - package: example.org/foo/bar/v3/api/ext
+      alias: ext
+`,
+			wantOk: true,
+		},
+		{
+			name:       "should update import mapping in JSON configuration",
+			modulePath: "example.org/foo/bar/v2",
+			config:     `{"package": "api", "import-mapping": {"./common.yaml": "example.org/foo/bar/api/common"}}`,
+			wantOut:    `{"package": "api", "import-mapping": {"./common.yaml": "example.org/foo/bar/v2/api/common"}}`,
+			wantOk:     true,
+		},
+		{
+			name:       "should not update non-matching module paths",
+			modulePath: "example.org/foo/bar/v2",
+			config: `import-mapping:
+  ./common.yaml: example.org/other/api
+`,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.config), &bytes.Buffer{}
+
+			ok, err := UpdateOapiCodegenConfig(tt.modulePath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateOapiCodegenConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateOapiCodegenConfig() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateOapiCodegenConfig() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
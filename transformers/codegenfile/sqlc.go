@@ -0,0 +1,22 @@
+package codegenfile
+
+import "github.com/danilvpetrov/gobump/transformers"
+
+// sqlcSchema describes the package references in sqlc configuration.
+//
+// See https://docs.sqlc.dev/en/latest/reference/config.html for reference.
+var sqlcSchema = schema{
+	{path: "**.go_type", kind: typeValue},
+	{path: "**.go_type.import", kind: packageValue},
+}
+
+// UpdateSqlcConfig updates the Go module paths in sqlc configuration file, such
+// as sqlc.yaml or sqlc.json.
+//
+// It updates the types referenced by 'go_type' overrides, either in a short
+// "example.org/foo.Type" form or using the 'import' field.
+func UpdateSqlcConfig(
+	modulePaths ...string,
+) transformers.Func {
+	return updateConfig(modulePaths, sqlcSchema)
+}
@@ -0,0 +1,129 @@
+package codegenfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/codegenfile"
+)
+
+func TestUpdateSqlcConfig(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		config     string
+		wantOut    string
+		wantOk     bool
+		wantErr    bool
+	}{
+		{
+			name:       "should update go_type overrides in YAML configuration",
+			modulePath: "example.org/foo/bar/v2",
+			config: `version: "2"
+sql:
+  - engine: "postgresql"
+    queries: "query.sql"
+    schema: "schema.sql"
+    gen:
+      go:
+        package: "db"
+        out: "db"
+        overrides:
+          - db_type: "uuid"
+            go_type: "example.org/foo/bar/types.UUID"
+          - column: "users.meta"
+            go_type:
+              import: "example.org/foo/bar/meta"
+              package: "meta"
+              type: "Meta"
+`,
+			wantOut: `version: "2"
+sql:
+  - engine: "postgresql"
+    queries: "query.sql"
+    schema: "schema.sql"
+    gen:
+      go:
+        package: "db"
+        out: "db"
+        overrides:
+          - db_type: "uuid"
+            go_type: "example.org/foo/bar/v2/types.UUID"
+          - column: "users.meta"
+            go_type:
+              import: "example.org/foo/bar/v2/meta"
+              package: "meta"
+              type: "Meta"
+`,
+			wantOk: true,
+		},
+		{
+			name:       "should update go_type overrides in JSON configuration",
+			modulePath: "example.org/foo/bar/v2",
+			config: `{
+  "version": "1",
+  "packages": [
+    {
+      "name": "db",
+      "path": "example.org/foo/bar/db",
+      "overrides": [
+        {"db_type": "uuid", "go_type": "example.org/foo/bar/types.UUID"},
+        {"column": "users.meta", "go_type": {"import": "example.org/foo/bar/meta", "type": "Meta"}}
+      ]
+    }
+  ]
+}
+`,
+			wantOut: `{
+  "version": "1",
+  "packages": [
+    {
+      "name": "db",
+      "path": "example.org/foo/bar/db",
+      "overrides": [
+        {"db_type": "uuid", "go_type": "example.org/foo/bar/v2/types.UUID"},
+        {"column": "users.meta", "go_type": {"import": "example.org/foo/bar/v2/meta", "type": "Meta"}}
+      ]
+    }
+  ]
+}
+`,
+			wantOk: true,
+		},
+		{
+			name:       "should not update non-matching module paths",
+			modulePath: "example.org/foo/bar/v2",
+			config: `overrides:
+  go:
+    overrides:
+      - db_type: "uuid"
+        go_type: "github.com/google/uuid.UUID"
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should return an error if JSON configuration is invalid",
+			modulePath: "example.org/foo/bar/v2",
+			config:     `{"overrides": [}`,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.config), &bytes.Buffer{}
+
+			ok, err := UpdateSqlcConfig(tt.modulePath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateSqlcConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateSqlcConfig() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateSqlcConfig() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,239 @@
+package codegenfile
+
+import (
+	"bytes"
+	"strings"
+)
+
+// yamlKey is a key of a YAML mapping or a list item located at the given
+// indentation level.
+type yamlKey struct {
+	indent int
+	key    string
+}
+
+// rewriteYAML rewrites the scalar values of a YAML document using the rewrite
+// function.
+//
+// It is a line-oriented parser supporting the subset of YAML used in the
+// configuration files: block mappings, block sequences, flow sequences of
+// scalars and plain or quoted scalars. The document layout, comments and
+// quoting styles are preserved.
+func rewriteYAML(
+	in []byte,
+	rewrite rewriteFunc,
+	report reportFunc,
+) (_ []byte, ok bool, _ error) {
+	var (
+		out         bytes.Buffer
+		isModified  bool
+		stack       []yamlKey
+		blockIndent = -1
+	)
+
+	for n, l := range bytes.SplitAfter(in, []byte("\n")) {
+		line := string(l)
+		content := strings.TrimRight(line, "\r\n")
+		trimmed := strings.TrimLeft(content, " ")
+		indent := len(content) - len(trimmed)
+
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") || trimmed == "---" {
+			out.WriteString(line)
+			continue
+		}
+
+		// Skip the contents of block scalars.
+		if blockIndent >= 0 {
+			if indent > blockIndent {
+				out.WriteString(line)
+				continue
+			}
+			blockIndent = -1
+		}
+
+		isItem := strings.HasPrefix(trimmed, "- ") || trimmed == "-"
+
+		// Leave only the parents of the current line on the stack. Block
+		// sequences are allowed to have the same indentation as their key.
+		for len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.indent < indent ||
+				(isItem && top.indent == indent && top.key != "[]") {
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+
+		pos := indent
+		for strings.HasPrefix(content[pos:], "- ") || content[pos:] == "-" {
+			stack = append(stack, yamlKey{indent: pos, key: "[]"})
+			pos++
+			for pos < len(content) && content[pos] == ' ' {
+				pos++
+			}
+		}
+
+		keys := stackKeys(stack)
+		valuePos := pos
+
+		if k, vp, isKV := splitYAMLKey(content, pos); isKV {
+			keys = append(keys, k)
+			valuePos = vp
+			stack = append(stack, yamlKey{indent: pos, key: k})
+		}
+
+		v := strings.TrimSpace(stripYAMLComment(content[valuePos:]))
+		if strings.HasPrefix(v, "|") || strings.HasPrefix(v, ">") {
+			blockIndent = indent
+		}
+
+		ee, err := rewriteYAMLValue(content, valuePos, keys, rewrite)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(ee) > 0 {
+			isModified = true
+			for _, e := range ee {
+				report(n+1, e.start+1, content[e.start:e.end], e.text)
+			}
+			line = applyEdits(content, ee) + line[len(content):]
+		}
+
+		out.WriteString(line)
+	}
+
+	return out.Bytes(), isModified, nil
+}
+
+// rewriteYAMLValue returns the edits rewriting the scalar value or the items
+// of the flow sequence starting at position pos of the line.
+func rewriteYAMLValue(
+	line string,
+	pos int,
+	keys []string,
+	rewrite rewriteFunc,
+) ([]edit, error) {
+	value := stripYAMLComment(line[pos:])
+
+	start := pos + len(value) - len(strings.TrimLeft(value, " \t"))
+	end := pos + len(strings.TrimRight(value, " \t"))
+	if start >= end {
+		return nil, nil
+	}
+
+	var ee []edit
+
+	if v := line[start:end]; v[0] == '[' && v[len(v)-1] == ']' {
+		// Flow sequence of scalars.
+		keys = append(keys, "[]")
+		is := start + 1
+		for _, item := range strings.Split(v[1:len(v)-1], ",") {
+			s := is + len(item) - len(strings.TrimLeft(item, " \t"))
+			e := is + len(strings.TrimRight(item, " \t"))
+			is += len(item) + 1
+
+			if s >= e {
+				continue
+			}
+
+			ed, ok, err := rewriteYAMLScalar(line, s, e, keys, rewrite)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				ee = append(ee, ed)
+			}
+		}
+	} else {
+		ed, ok, err := rewriteYAMLScalar(line, start, end, keys, rewrite)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ee = append(ee, ed)
+		}
+	}
+
+	return ee, nil
+}
+
+// rewriteYAMLScalar rewrites the plain or quoted scalar occupying the
+// [start, end) range of the line.
+func rewriteYAMLScalar(
+	line string,
+	start, end int,
+	keys []string,
+	rewrite rewriteFunc,
+) (_ edit, ok bool, _ error) {
+	if q := line[start]; (q == '"' || q == '\'') && end-start >= 2 && line[end-1] == q {
+		start, end = start+1, end-1
+	}
+
+	nv, ok, err := rewrite(keys, line[start:end])
+	if err != nil || !ok {
+		return edit{}, false, err
+	}
+
+	return edit{start: start, end: end, text: nv}, true, nil
+}
+
+// splitYAMLKey splits a "key: value" pair starting at position pos of the
+// line. It returns the key and the position of the value.
+func splitYAMLKey(line string, pos int) (key string, valuePos int, ok bool) {
+	rest := line[pos:]
+	if rest == "" || rest[0] == '[' || rest[0] == '{' {
+		return "", 0, false
+	}
+
+	var i int
+	if q := rest[0]; q == '"' || q == '\'' {
+		j := strings.IndexByte(rest[1:], q)
+		if j < 0 {
+			return "", 0, false
+		}
+		key, i = rest[1:j+1], j+2
+		if !strings.HasPrefix(rest[i:], ":") {
+			return "", 0, false
+		}
+	} else {
+		i = strings.Index(rest, ": ")
+		if i < 0 {
+			if !strings.HasSuffix(rest, ":") {
+				return "", 0, false
+			}
+			i = len(rest) - 1
+		}
+		key = strings.TrimSpace(rest[:i])
+	}
+
+	return key, pos + i + 1, true
+}
+
+// stripYAMLComment strips the trailing comment from a YAML value.
+func stripYAMLComment(v string) string {
+	var quote byte
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || v[i-1] == ' ' || v[i-1] == '\t'):
+			return v[:i]
+		}
+	}
+
+	return v
+}
+
+// stackKeys returns the keys of the stack.
+func stackKeys(stack []yamlKey) []string {
+	keys := make([]string, 0, len(stack)+1)
+	for _, k := range stack {
+		keys = append(keys, k.key)
+	}
+
+	return keys
+}
@@ -0,0 +1,175 @@
+package transformers
+
+import (
+	"fmt"
+	"io"
+	"log"
+)
+
+// FileTransformer transforms the content of a file read from r and writes
+// the transformed content to w.
+//
+// It MUST return ok as true if the content read from r is changed and written
+// to w.
+type FileTransformer interface {
+	TransformFile(ctx *Context, in io.Reader, out io.Writer) (ok bool, err error)
+}
+
+// Func is a FileTransformer implemented as a function. The context may be
+// nil, in which case the changes are not reported.
+type Func func(ctx *Context, in io.Reader, out io.Writer) (ok bool, err error)
+
+// TransformFile calls f(ctx, in, out).
+func (f Func) TransformFile(ctx *Context, in io.Reader, out io.Writer) (bool, error) {
+	return f(ctx, in, out)
+}
+
+// Transformer returns a Transformer calling f without a context, which adapts
+// f to the code written against the Transformer type.
+func (f Func) Transformer() Transformer {
+	return func(in io.Reader, out io.Writer) (bool, error) {
+		return f(nil, in, out)
+	}
+}
+
+// TransformFile calls t(in, out) ignoring the context. It allows using the
+// transformers that are not aware of the context as a FileTransformer.
+func (t Transformer) TransformFile(_ *Context, in io.Reader, out io.Writer) (bool, error) {
+	return t(in, out)
+}
+
+// Module describes a Go module.
+type Module struct {
+	// Dir is the slash-separated path of the module root directory.
+	Dir string
+
+	// Path is the module path.
+	Path string
+
+	// Requires is a list of direct dependency module paths.
+	Requires []string
+}
+
+// Change is a record of a module path replaced in a file.
+type Change struct {
+	// Path is the slash-separated path of the changed file.
+	Path string `json:"path"`
+
+	// Transformer is the name of the transformer that made the change.
+	Transformer string `json:"transformer,omitempty"`
+
+	// Line and Column is the 1-based position of the replaced path in the
+	// original file. Column is measured in bytes.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+
+	// Old and New are the replaced and the new paths.
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// Warning is a record of a problem found in a file that the transformer could
+// not resolve by itself, such as a change that leaves the file broken.
+type Warning struct {
+	// Path is the slash-separated path of the file.
+	Path string `json:"path"`
+
+	// Transformer is the name of the transformer that found the problem.
+	Transformer string `json:"transformer,omitempty"`
+
+	// Message is the description of the problem.
+	Message string `json:"message"`
+}
+
+// Context is the information about the file being transformed.
+//
+// All methods of a nil or empty context are safe to call.
+type Context struct {
+	// Path is the slash-separated path of the file relative to the root of
+	// the processed tree.
+	Path string
+
+	// Transformer is the name of the transformer processing the file.
+	Transformer string
+
+	// Module is the module owning the file. It is nil if the file does not
+	// belong to any module.
+	Module *Module
+
+	// Modules is a list of the paths of the other modules known to own
+	// packages, such as the modules nested in the processed tree. Along with
+	// the owning module and its requirements, it is used to attribute the
+	// import paths to the modules owning them.
+	Modules []string
+
+	// Logger receives the messages about the transformation. If it is nil,
+	// the messages are discarded.
+	Logger *log.Logger
+
+	// OnChange is called for every change made to the file. If it is nil,
+	// the changes are not recorded.
+	OnChange func(Change)
+
+	// OnWarning is called for every problem found in the file that requires
+	// attention. If it is nil, the problems are logged instead.
+	OnWarning func(Warning)
+}
+
+// Report records a change of the path at the given position in the file.
+func (c *Context) Report(line, column int, oldPath, newPath string) {
+	if c == nil || c.OnChange == nil {
+		return
+	}
+
+	c.OnChange(Change{
+		Path:        c.Path,
+		Transformer: c.Transformer,
+		Line:        line,
+		Column:      column,
+		Old:         oldPath,
+		New:         newPath,
+	})
+}
+
+// KnownModules returns the paths of the modules known to own packages: the
+// owning module, its requirements and the other modules of the context.
+func (c *Context) KnownModules() []string {
+	if c == nil {
+		return nil
+	}
+
+	var res []string
+	if c.Module != nil {
+		res = append(res, c.Module.Path)
+		res = append(res, c.Module.Requires...)
+	}
+
+	return append(res, c.Modules...)
+}
+
+// Logf logs a message about the file.
+func (c *Context) Logf(format string, args ...any) {
+	if c == nil || c.Logger == nil {
+		return
+	}
+
+	c.Logger.Printf(c.Path+": "+format, args...)
+}
+
+// Warnf records a problem found in the file that requires attention.
+func (c *Context) Warnf(format string, args ...any) {
+	if c == nil {
+		return
+	}
+
+	if c.OnWarning == nil {
+		c.Logf("warning: "+format, args...)
+		return
+	}
+
+	c.OnWarning(Warning{
+		Path:        c.Path,
+		Transformer: c.Transformer,
+		Message:     fmt.Sprintf(format, args...),
+	})
+}
@@ -0,0 +1,145 @@
+package transformers_test
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestTransformer_TransformFile(t *testing.T) {
+	var ft FileTransformer = Transformer(func(in io.Reader, out io.Writer) (bool, error) {
+		_, err := io.Copy(out, in)
+		return true, err
+	})
+
+	var out bytes.Buffer
+	ok, err := ft.TransformFile(&Context{Path: "foo.go"}, strings.NewReader("foo"), &out)
+	if err != nil {
+		t.Fatalf("TransformFile() error = %v", err)
+	}
+
+	if !ok || out.String() != "foo" {
+		t.Fatalf("TransformFile() ok = %v, out = %q, want true and %q", ok, out.String(), "foo")
+	}
+}
+
+func TestFunc_Transformer(t *testing.T) {
+	f := Func(func(ctx *Context, in io.Reader, out io.Writer) (bool, error) {
+		ctx.Report(1, 1, "example.org/foo", "example.org/foo/v2")
+		_, err := io.Copy(out, in)
+		return true, err
+	})
+
+	var out bytes.Buffer
+	ok, err := f.Transformer()(strings.NewReader("foo"), &out)
+	if err != nil {
+		t.Fatalf("Transformer() error = %v", err)
+	}
+
+	if !ok || out.String() != "foo" {
+		t.Fatalf("Transformer() ok = %v, out = %q, want true and %q", ok, out.String(), "foo")
+	}
+}
+
+func TestContext(t *testing.T) {
+	t.Run("should record changes and log messages", func(t *testing.T) {
+		var (
+			got  []Change
+			logs bytes.Buffer
+		)
+
+		ctx := &Context{
+			Path:        "foo.go",
+			Transformer: "go-imports",
+			Logger:      log.New(&logs, "", 0),
+			OnChange:    func(c Change) { got = append(got, c) },
+		}
+
+		ctx.Report(3, 2, "example.org/foo", "example.org/foo/v2")
+		ctx.Logf("skipping %s", "something")
+
+		want := []Change{{
+			Path:        "foo.go",
+			Transformer: "go-imports",
+			Line:        3,
+			Column:      2,
+			Old:         "example.org/foo",
+			New:         "example.org/foo/v2",
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Report() recorded %+v, want %+v", got, want)
+		}
+
+		if l := logs.String(); l != "foo.go: skipping something\n" {
+			t.Fatalf("Logf() logged %q", l)
+		}
+	})
+
+	t.Run("should record warnings", func(t *testing.T) {
+		var got []Warning
+
+		ctx := &Context{
+			Path:        "foo.go",
+			Transformer: "go-imports",
+			OnWarning:   func(w Warning) { got = append(got, w) },
+		}
+
+		ctx.Warnf("cannot merge %s", "something")
+
+		want := []Warning{{
+			Path:        "foo.go",
+			Transformer: "go-imports",
+			Message:     "cannot merge something",
+		}}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("Warnf() recorded %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("should log warnings if they are not recorded", func(t *testing.T) {
+		var logs bytes.Buffer
+
+		ctx := &Context{
+			Path:   "foo.go",
+			Logger: log.New(&logs, "", 0),
+		}
+
+		ctx.Warnf("cannot merge %s", "something")
+
+		if l := logs.String(); l != "foo.go: warning: cannot merge something\n" {
+			t.Fatalf("Warnf() logged %q", l)
+		}
+	})
+
+	t.Run("should be safe to use if nil", func(t *testing.T) {
+		var ctx *Context
+		ctx.Report(1, 1, "example.org/foo", "example.org/foo/v2")
+		ctx.Logf("message")
+		ctx.Warnf("message")
+	})
+}
+
+func TestContext_KnownModules(t *testing.T) {
+	var nilCtx *Context
+	if got := nilCtx.KnownModules(); got != nil {
+		t.Fatalf("KnownModules() = %v, want nil", got)
+	}
+
+	ctx := &Context{
+		Module: &Module{
+			Path:     "example.org/foo",
+			Requires: []string{"example.org/bar"},
+		},
+		Modules: []string{"example.org/foo/sub"},
+	}
+
+	want := []string{"example.org/foo", "example.org/bar", "example.org/foo/sub"}
+	if got := ctx.KnownModules(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("KnownModules() = %v, want %v", got, want)
+	}
+}
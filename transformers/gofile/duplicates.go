@@ -0,0 +1,176 @@
+package gofile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// mergeDuplicateImports merges the imports of the given paths declared by
+// several import specs. It happens if the file imports two major versions of
+// the same module and one of them is rewritten to the other.
+//
+// Only one spec is kept for every path, and the identifiers qualified with
+// the names of the removed specs are rewritten to the name of the kept one.
+// If that cannot be done safely, the duplicate imports are left intact and
+// a warning is reported to the context.
+func mergeDuplicateImports(
+	ctx *transformers.Context,
+	fset *token.FileSet,
+	f *ast.File,
+	paths []string,
+) {
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		var specs []*ast.ImportSpec
+		for _, i := range f.Imports {
+			if importPath(i) == p {
+				specs = append(specs, i)
+			}
+		}
+
+		if len(specs) < 2 {
+			continue
+		}
+
+		keep, err := importToKeep(f, p, specs)
+		if err != nil {
+			pos := fset.Position(specs[0].Pos())
+			ctx.Warnf("cannot merge the imports of %s at line %d: %s", p, pos.Line, err)
+			continue
+		}
+
+		name := importName(keep, p)
+		renames := map[string]bool{}
+		for _, s := range specs {
+			if n := importName(s, p); s != keep && n != "_" && n != name {
+				renames[n] = true
+			}
+		}
+
+		renameQualifiers(f, renames, name)
+
+		for i, s := range specs {
+			if s == keep {
+				continue
+			}
+
+			// DeleteNamedImport deletes all the specs with the given name and
+			// path, so the spec is given a unique name first.
+			if s.Name == nil {
+				s.Name = &ast.Ident{NamePos: s.Path.Pos()}
+			}
+			s.Name.Name = fmt.Sprintf("gobump_duplicate_%d", i)
+			astutil.DeleteNamedImport(fset, f, s.Name.Name, p)
+		}
+	}
+}
+
+// importToKeep returns the spec to keep out of the specs importing the same
+// path.
+//
+// The unnamed imports are preferred, as their name is the most natural one.
+// The blank imports are only kept if all the specs are blank. The name of the
+// kept spec must not be declared in the file, otherwise the rewritten
+// qualified identifiers could refer to the declaration instead of the
+// package.
+func importToKeep(f *ast.File, path string, specs []*ast.ImportSpec) (*ast.ImportSpec, error) {
+	var candidates []*ast.ImportSpec
+	for _, s := range specs {
+		switch importName(s, path) {
+		case ".":
+			return nil, fmt.Errorf("dot imports are not supported")
+		case "_":
+			continue
+		}
+
+		if s.Name == nil {
+			candidates = append([]*ast.ImportSpec{s}, candidates...)
+		} else {
+			candidates = append(candidates, s)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return specs[0], nil
+	}
+
+	declared := declaredNames(f)
+
+	var names []string
+	for _, s := range candidates {
+		n := importName(s, path)
+		if !declared[n] || !hasOtherNames(s, path, specs) {
+			return s, nil
+		}
+		names = append(names, n)
+	}
+
+	return nil, fmt.Errorf("names %s are shadowed by the declarations in the file", strings.Join(names, ", "))
+}
+
+// hasOtherNames reports if any of the non-blank specs other than s import
+// the path under a different name.
+func hasOtherNames(s *ast.ImportSpec, path string, specs []*ast.ImportSpec) bool {
+	name := importName(s, path)
+	for _, o := range specs {
+		if n := importName(o, path); o != s && n != "_" && n != name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// declaredNames returns the names of the objects declared in the file,
+// including the local ones.
+func declaredNames(f *ast.File) map[string]bool {
+	res := map[string]bool{}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if id, ok := n.(*ast.Ident); ok && id.Obj != nil {
+			res[id.Name] = true
+		}
+
+		return true
+	})
+
+	return res
+}
+
+// renameQualifiers renames the package names in the qualified identifiers.
+// The identifiers resolved to the declarations in the file are not renamed.
+func renameQualifiers(f *ast.File, names map[string]bool, name string) {
+	if len(names) == 0 {
+		return
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil && names[id.Name] {
+			id.Name = name
+		}
+
+		return true
+	})
+}
+
+// importName returns the name the package is imported under. For the unnamed
+// imports the name is assumed from the import path.
+func importName(i *ast.ImportSpec, path string) string {
+	if i.Name != nil {
+		return i.Name.Name
+	}
+
+	return pathx.AssumedPackageName(path)
+}
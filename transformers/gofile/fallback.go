@@ -0,0 +1,175 @@
+package gofile
+
+import (
+	"bytes"
+	"go/ast"
+	"go/scanner"
+	"go/token"
+	"io"
+	"strconv"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// importLit is an import path literal found in a Go source.
+type importLit struct {
+	offset int    // byte offset of the literal, including the quote
+	lit    string // literal as written in the source
+	pos    token.Position
+}
+
+// specLits returns the import path literals of the import specs parsed from
+// the source.
+func specLits(fset *token.FileSet, specs []*ast.ImportSpec) []importLit {
+	var res []importLit
+	for _, i := range specs {
+		pos := fset.Position(i.Path.Pos())
+		res = append(res, importLit{
+			offset: pos.Offset,
+			lit:    i.Path.Value,
+			pos:    pos,
+		})
+	}
+
+	return res
+}
+
+// scanLits returns the import path literals of a Go source that cannot be
+// parsed, by scanning the tokens of the package clause and the import
+// declarations.
+//
+// It returns false if the tokens do not form a valid package clause followed
+// by the import declarations, in which case the source is unlikely to be Go
+// at all, for example a template.
+func scanLits(src []byte) ([]importLit, bool) {
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	var (
+		s   scanner.Scanner
+		res []importLit
+	)
+	s.Init(file, src, nil, 0)
+
+	if _, tok, _ := s.Scan(); tok != token.PACKAGE {
+		return nil, false
+	}
+	if _, tok, _ := s.Scan(); tok != token.IDENT {
+		return nil, false
+	}
+	if _, tok, _ := s.Scan(); tok != token.SEMICOLON {
+		return nil, false
+	}
+
+	// spec scans an import spec starting with the given token. It returns
+	// false if the spec is invalid.
+	spec := func(pos token.Pos, tok token.Token, lit string) bool {
+		if tok == token.IDENT || tok == token.PERIOD {
+			pos, tok, lit = s.Scan()
+		}
+		if tok != token.STRING {
+			return false
+		}
+
+		p := fset.Position(pos)
+		res = append(res, importLit{offset: p.Offset, lit: lit, pos: p})
+
+		return true
+	}
+
+	// end reports if the token ends a declaration.
+	end := func(tok token.Token) bool {
+		return tok == token.SEMICOLON || tok == token.EOF
+	}
+
+	for {
+		if _, tok, _ := s.Scan(); tok != token.IMPORT {
+			// The imports end with the first declaration that is not an
+			// import.
+			return res, true
+		}
+
+		pos, tok, lit := s.Scan()
+		if tok != token.LPAREN {
+			if !spec(pos, tok, lit) {
+				return nil, false
+			}
+			if _, tok, _ := s.Scan(); !end(tok) {
+				return nil, false
+			}
+			continue
+		}
+
+		for {
+			pos, tok, lit := s.Scan()
+			if tok == token.RPAREN {
+				break
+			}
+			if !spec(pos, tok, lit) {
+				return nil, false
+			}
+
+			_, tok, _ = s.Scan()
+			if tok == token.RPAREN {
+				break
+			}
+			if tok != token.SEMICOLON {
+				return nil, false
+			}
+		}
+
+		if _, tok, _ := s.Scan(); !end(tok) {
+			return nil, false
+		}
+	}
+}
+
+// rewriteLits rewrites the import path literals in the source in place,
+// leaving the rest of the source intact. It is used for the sources that
+// cannot be parsed and formatted.
+func rewriteLits(
+	ctx *transformers.Context,
+	src []byte,
+	lits []importLit,
+	newImportPaths []string,
+	modules []string,
+	out io.Writer,
+) (bool, error) {
+	var (
+		buf  bytes.Buffer
+		last int
+	)
+	for _, l := range lits {
+		p, err := strconv.Unquote(l.lit)
+		if err != nil {
+			continue
+		}
+
+		np, ok, err := pathx.UpdateImportPathAny(newImportPaths, p, modules...)
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			continue
+		}
+
+		buf.Write(src[last:l.offset])
+		buf.WriteString(strconv.Quote(np))
+		last = l.offset + len(l.lit)
+
+		ctx.Report(l.pos.Line, l.pos.Column, p, np)
+	}
+
+	if last == 0 {
+		return false, nil
+	}
+
+	buf.Write(src[last:])
+
+	if _, err := buf.WriteTo(out); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,172 @@
+package gofile
+
+import (
+	"bytes"
+	"io"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+const generatePrefix = "//go:generate "
+
+// UpdateMockgenDirectives replaces the package paths in mockgen's
+// //go:generate directives with the new import paths applicable to them.
+//
+// It updates the package path of the reflect mode and the package paths
+// passed to the flags, such as -self_package and -imports.
+func UpdateMockgenDirectives(
+	newImportPaths ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		pfxs, err := pathx.ModulePrefixes(newImportPaths)
+		if err != nil {
+			return false, err
+		}
+
+		src, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		// Most of the files do not mention the module at all, so avoid
+		// scanning their lines.
+		if !pathx.ContainsAny(src, pfxs) {
+			return false, nil
+		}
+
+		// The updated directives are spliced into the source, so that the
+		// rest of it, including the line endings, is left intact.
+		var (
+			buf  bytes.Buffer
+			last int
+			line int
+		)
+		for start := 0; start < len(src); {
+			end := bytes.IndexByte(src[start:], '\n')
+			if end < 0 {
+				end = len(src)
+			} else {
+				end += start
+			}
+			line++
+
+			if bytes.HasPrefix(src[start:end], []byte(generatePrefix)) {
+				l := strings.TrimSuffix(string(src[start:end]), "\r")
+
+				if strings.Contains(l, "mockgen") {
+					report := func(offset int, oldPath, newPath string) {
+						ctx.Report(line, offset+1, oldPath, newPath)
+					}
+
+					nl, ok, err := updateDirective(l, newImportPaths, ctx.KnownModules(), report)
+					if err != nil {
+						return false, err
+					}
+					if ok {
+						buf.Write(src[last:start])
+						buf.WriteString(nl)
+						last = start + len(l)
+					}
+				}
+			}
+
+			start = end + 1
+		}
+
+		if last == 0 {
+			return false, nil
+		}
+
+		buf.Write(src[last:])
+
+		if _, err := buf.WriteTo(out); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// reportFunc reports a path replaced at the given byte offset of a line.
+type reportFunc func(offset int, oldPath, newPath string)
+
+// updateDirective updates the package paths in the arguments of a
+// //go:generate directive. If the directive is not updated, ok is returned as
+// false.
+func updateDirective(
+	directive string,
+	newImportPaths []string,
+	modules []string,
+	report reportFunc,
+) (_ string, ok bool, _ error) {
+	args := strings.Split(directive[len(generatePrefix):], " ")
+
+	var isModified bool
+	offset := len(generatePrefix)
+	for i, a := range args {
+		argOffset := offset
+		na, ok, err := updateArg(a, newImportPaths, modules, func(o int, op, np string) {
+			report(argOffset+o, op, np)
+		})
+		if err != nil {
+			return "", false, err
+		}
+		if ok {
+			isModified = true
+			args[i] = na
+		}
+
+		offset += len(a) + 1
+	}
+
+	if !isModified {
+		return "", false, nil
+	}
+
+	return generatePrefix + strings.Join(args, " "), true, nil
+}
+
+// updateArg updates the package paths in a single directive argument, such as
+// "example.org/foo", "-self_package=example.org/foo" or
+// "-imports=foo=example.org/foo,bar=example.org/bar".
+func updateArg(
+	arg string,
+	newImportPaths []string,
+	modules []string,
+	report reportFunc,
+) (_ string, ok bool, _ error) {
+	var (
+		b          strings.Builder
+		isModified bool
+		last       int
+	)
+
+	for i := 0; i <= len(arg); i++ {
+		if i < len(arg) && !strings.ContainsRune(`=,"'`, rune(arg[i])) {
+			continue
+		}
+
+		p := arg[last:i]
+		if !strings.HasPrefix(p, "-") {
+			np, ok, err := pathx.UpdateImportPathAny(newImportPaths, p, modules...)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				isModified = true
+				report(last, p, np)
+				p = np
+			}
+		}
+
+		b.WriteString(p)
+		if i < len(arg) {
+			b.WriteByte(arg[i])
+		}
+		last = i + 1
+	}
+
+	return b.String(), isModified, nil
+}
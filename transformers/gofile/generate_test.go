@@ -0,0 +1,103 @@
+package gofile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/gofile"
+)
+
+func TestUpdateMockgenDirectives(t *testing.T) {
+	tests := []struct {
+		name          string
+		gofile        string
+		newImportPath string
+		wantOk        bool
+		wantErr       bool
+		wantOut       string
+	}{
+		{
+			name: "updates reflect mode package path to a newer version",
+			gofile: `package foo
+
+//go:generate mockgen -destination=mocks/foo.go example.org/foo/bar/pkg Foo,Bar
+//go:generate go run go.uber.org/mock/mockgen -destination=mocks/baz.go example.org/foo/bar Baz
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        true,
+			wantOut: `package foo
+
+//go:generate mockgen -destination=mocks/foo.go example.org/foo/bar/v2/pkg Foo,Bar
+//go:generate go run go.uber.org/mock/mockgen -destination=mocks/baz.go example.org/foo/bar/v2 Baz
+`,
+		},
+		{
+			name: "updates package paths in flags to an older version",
+			gofile: `package foo
+
+//go:generate mockgen -source=foo.go -self_package=example.org/foo/bar/v2/mocks -imports=bar=example.org/foo/bar/v2/pkg,baz=example.org/baz
+`,
+			newImportPath: "example.org/foo/bar",
+			wantOk:        true,
+			wantOut: `package foo
+
+//go:generate mockgen -source=foo.go -self_package=example.org/foo/bar/mocks -imports=bar=example.org/foo/bar/pkg,baz=example.org/baz
+`,
+		},
+		{
+			name: "preserves line endings and the missing final newline",
+			gofile: "package foo\r\n" +
+				"\r\n" +
+				"//go:generate mockgen -destination=mocks/foo.go example.org/foo/bar Foo\r\n" +
+				"//go:generate mockgen -destination=mocks/bar.go example.org/foo/bar/pkg Bar",
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        true,
+			wantOut: "package foo\r\n" +
+				"\r\n" +
+				"//go:generate mockgen -destination=mocks/foo.go example.org/foo/bar/v2 Foo\r\n" +
+				"//go:generate mockgen -destination=mocks/bar.go example.org/foo/bar/v2/pkg Bar",
+		},
+		{
+			name: "returns ok as false for directives of other tools",
+			gofile: `package foo
+
+//go:generate stringer -type=Foo example.org/foo/bar
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
+		},
+		{
+			name: "returns ok as false if package path is not matching",
+			gofile: `package foo
+
+//go:generate mockgen -destination=mocks/foo.go example.org/bar/foo Foo
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
+		},
+		{
+			name:          "returns an error if a new import path is invalid",
+			gofile:        "//go:generate mockgen example.org/foo/bar Foo\n",
+			newImportPath: "example.org/foo/bar/v1",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.gofile), &bytes.Buffer{}
+
+			ok, err := UpdateMockgenDirectives(tt.newImportPath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateMockgenDirectives() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateMockgenDirectives() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateMockgenDirectives() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
@@ -8,8 +8,8 @@ import (
 	"io"
 	"strconv"
 
+	"github.com/danilvpetrov/gobump/internal/pathx"
 	"github.com/danilvpetrov/gobump/transformers"
-	"github.com/danilvpetrov/gobump/transformers/internal/pathx"
 	"golang.org/x/tools/go/ast/astutil"
 )
 
@@ -18,28 +18,92 @@ import (
 func UpdateImports(
 	newImportPath string,
 ) transformers.Transformer {
-	return func(in io.Reader, out io.Writer) (ok bool, err error) {
+	return UpdateImportsFunc(newImportPath).Transformer()
+}
+
+// UpdateImportsFunc replaces the import paths in a .go file with the new
+// import paths applicable to them, reporting the replaced paths to the
+// context.
+//
+// The files that do not mention the module path prefix are not parsed at all,
+// and the files that do are only parsed in full if their imports are to be
+// updated.
+//
+// The files that cannot be parsed, such as the half-written files, have the
+// import paths rewritten in place, as long as the package clause and the
+// imports are well-formed. Otherwise, the parse error is returned.
+//
+// If the file imports several major versions of the module, the imports
+// ending up with the same path are merged, and the qualified identifiers are
+// rewritten to the name of the remaining import.
+func UpdateImportsFunc(
+	newImportPaths ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		pfxs, err := pathx.ModulePrefixes(newImportPaths)
+		if err != nil {
+			return false, err
+		}
+
+		src, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		// Most of the files do not import the module at all, so avoid
+		// parsing them.
+		if !pathx.ContainsAny(src, pfxs) {
+			return false, nil
+		}
+
 		fset := token.NewFileSet()
-		f, err := parser.ParseFile(fset, "", in, parser.ParseComments)
+
+		// Parse the imports first, as it is much cheaper than parsing the
+		// whole file, and the prefix may be mentioned outside of the imports.
+		modules := ctx.KnownModules()
+
+		f, err := parser.ParseFile(fset, "", src, parser.ImportsOnly)
 		if err != nil {
+			lits, ok := scanLits(src)
+			if !ok {
+				return false, err
+			}
+
+			return rewriteUnparsed(ctx, src, lits, newImportPaths, modules, out, err)
+		}
+
+		if ok, err := hasImportsToUpdate(f, newImportPaths, modules); err != nil || !ok {
 			return false, err
 		}
 
-		var rewrote bool
+		specs := f.Imports
+
+		f, err = parser.ParseFile(fset, "", src, parser.ParseComments)
+		if err != nil {
+			return rewriteUnparsed(ctx, src, specLits(fset, specs), newImportPaths, modules, out, err)
+		}
+
+		var rewritten []string
 		for _, i := range f.Imports {
 			p := importPath(i)
-			np, ok, err := pathx.UpdateImportPath(newImportPath, p)
+			np, ok, err := pathx.UpdateImportPathAny(newImportPaths, p, modules...)
 			if err != nil {
 				return false, err
 			}
 			if ok {
-				rewrote = astutil.RewriteImport(fset, f, p, np)
+				pos := fset.Position(i.Path.Pos())
+				if astutil.RewriteImport(fset, f, p, np) {
+					rewritten = append(rewritten, np)
+					ctx.Report(pos.Line, pos.Column, p, np)
+				}
 			}
 		}
-		if !rewrote {
+		if len(rewritten) == 0 {
 			return false, nil
 		}
 
+		mergeDuplicateImports(ctx, fset, f, rewritten)
+
 		if err := format.Node(out, fset, f); err != nil {
 			return false, err
 		}
@@ -48,6 +112,19 @@ func UpdateImports(
 	}
 }
 
+// hasImportsToUpdate reports if any of the file's imports is to be updated to
+// one of the new import paths.
+func hasImportsToUpdate(f *ast.File, newImportPaths []string, modules []string) (bool, error) {
+	for _, i := range f.Imports {
+		_, ok, err := pathx.UpdateImportPathAny(newImportPaths, importPath(i), modules...)
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
 func importPath(i *ast.ImportSpec) string {
 	p, err := strconv.Unquote(i.Path.Value)
 	if err != nil {
@@ -56,3 +133,22 @@ func importPath(i *ast.ImportSpec) string {
 
 	return p
 }
+
+// rewriteUnparsed rewrites the import paths of a source that cannot be parsed
+// in full, and warns that the file needs attention.
+func rewriteUnparsed(
+	ctx *transformers.Context,
+	src []byte,
+	lits []importLit,
+	newImportPaths []string,
+	modules []string,
+	out io.Writer,
+	parseErr error,
+) (bool, error) {
+	ok, err := rewriteLits(ctx, src, lits, newImportPaths, modules, out)
+	if ok {
+		ctx.Warnf("the import paths are rewritten in place as the file cannot be parsed: %s", parseErr)
+	}
+
+	return ok, err
+}
@@ -2,8 +2,10 @@ package gofile_test
 
 import (
 	"bytes"
+	"reflect"
 	"testing"
 
+	"github.com/danilvpetrov/gobump/transformers"
 	. "github.com/danilvpetrov/gobump/transformers/gofile"
 )
 
@@ -233,9 +235,29 @@ func main() {
 `,
 		},
 		{
-			name:    "returns an error if .go file is not valid",
-			gofile:  "<invalid-go-file>",
-			wantErr: true,
+			name:          "returns an error if .go file is not valid",
+			gofile:        "<invalid-go-file> example.org/foo/bar",
+			newImportPath: "example.org/foo/bar/v2",
+			wantErr:       true,
+		},
+		{
+			name:          "returns ok as false without parsing if the module is not mentioned",
+			gofile:        "<invalid-go-file>",
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
+		},
+		{
+			name: "returns ok as false if the module is mentioned outside of imports",
+			gofile: `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("example.org/foo/bar")
+}
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
 		},
 		{
 			name: "returns an error if a new import path is invalid",
@@ -285,6 +307,63 @@ func main() {
 			newImportPath: "example.org/bar/foo",
 			wantOk:        false,
 		},
+		{
+			name: "rewrites import paths in place if the file cannot be parsed",
+			gofile: `package main
+
+import (
+	"fmt"
+	foo   "example.org/foo/bar"
+)
+
+func main() {
+	fmt.Println(foo.Hello
+}
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        true,
+			wantOut: `package main
+
+import (
+	"fmt"
+	foo   "example.org/foo/bar/v2"
+)
+
+func main() {
+	fmt.Println(foo.Hello
+}
+`,
+		},
+		{
+			name: "rewrites import paths in place if the imports cannot be parsed",
+			gofile: `package main
+
+import (
+	"example.org/foo/bar"
+	"not a valid path"
+)
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        true,
+			wantOut: `package main
+
+import (
+	"example.org/foo/bar/v2"
+	"not a valid path"
+)
+`,
+		},
+		{
+			name: "returns error if the file is not a Go source",
+			gofile: `package {{ .Package }}
+
+import (
+	"example.org/foo/bar"
+)
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantErr:       true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -305,3 +384,262 @@ func main() {
 		})
 	}
 }
+
+func TestUpdateImportsFunc_severalPaths(t *testing.T) {
+	r, w := bytes.NewBufferString(`package main
+
+import (
+	"example.org/bar/v2/pkg"
+	"example.org/baz"
+	"example.org/foo"
+)
+`), &bytes.Buffer{}
+
+	ok, err := UpdateImportsFunc("example.org/foo/v2", "example.org/bar/v3")(nil, r, w)
+	if err != nil {
+		t.Fatalf("UpdateImportsFunc() error = %v", err)
+	}
+
+	if !ok {
+		t.Fatal("UpdateImportsFunc() ok = false, want true")
+	}
+
+	want := `package main
+
+import (
+	"example.org/bar/v3/pkg"
+	"example.org/baz"
+	"example.org/foo/v2"
+)
+`
+	if out := w.String(); out != want {
+		t.Fatalf("UpdateImportsFunc() out = %s, want %s", out, want)
+	}
+}
+
+func TestUpdateImportsFunc_report(t *testing.T) {
+	var got []transformers.Change
+	ctx := &transformers.Context{
+		Path:     "main.go",
+		OnChange: func(c transformers.Change) { got = append(got, c) },
+	}
+
+	r, w := bytes.NewBufferString(`package main
+
+import (
+	"fmt"
+
+	foobar "example.org/foo/bar/sub"
+)
+
+func main() {
+	fmt.Println(foobar.Hello)
+}
+`), &bytes.Buffer{}
+
+	ok, err := UpdateImportsFunc("example.org/foo/bar/v2")(ctx, r, w)
+	if err != nil {
+		t.Fatalf("UpdateImportsFunc() error = %v", err)
+	}
+
+	if !ok {
+		t.Fatal("UpdateImportsFunc() ok = false, want true")
+	}
+
+	want := []transformers.Change{{
+		Path:   "main.go",
+		Line:   6,
+		Column: 9,
+		Old:    "example.org/foo/bar/sub",
+		New:    "example.org/foo/bar/v2/sub",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("UpdateImportsFunc() reported %+v, want %+v", got, want)
+	}
+}
+
+func TestUpdateImportsFunc_duplicates(t *testing.T) {
+	tests := []struct {
+		name         string
+		gofile       string
+		wantOut      string
+		wantWarnings []string
+	}{
+		{
+			name: "should merge the imports and rewrite the qualified identifiers",
+			gofile: `package main
+
+import (
+	"fmt"
+
+	"example.org/foo"
+	foov2 "example.org/foo/v2"
+)
+
+func main() {
+	fmt.Println(foo.Hello, foov2.Hello)
+}
+`,
+			wantOut: `package main
+
+import (
+	"fmt"
+
+	"example.org/foo/v2"
+)
+
+func main() {
+	fmt.Println(foo.Hello, foo.Hello)
+}
+`,
+		},
+		{
+			name: "should drop the duplicate blank import",
+			gofile: `package main
+
+import (
+	_ "example.org/foo"
+	"example.org/foo/v2"
+)
+
+var _ = foo.Hello
+`,
+			wantOut: `package main
+
+import (
+	"example.org/foo/v2"
+)
+
+var _ = foo.Hello
+`,
+		},
+		{
+			name: "should keep the aliased import if the assumed name is declared in the file",
+			gofile: `package main
+
+import (
+	"example.org/foo"
+	foov2 "example.org/foo/v2"
+)
+
+func main() {
+	var x = foo.Hello
+	{
+		foo := x
+		_ = foo.Len
+	}
+	_ = foov2.Hello
+}
+`,
+			wantOut: `package main
+
+import (
+	foov2 "example.org/foo/v2"
+)
+
+func main() {
+	var x = foov2.Hello
+	{
+		foo := x
+		_ = foo.Len
+	}
+	_ = foov2.Hello
+}
+`,
+		},
+		{
+			name: "should report the imports if all the names are declared in the file",
+			gofile: `package main
+
+import (
+	"example.org/foo"
+	foov2 "example.org/foo/v2"
+)
+
+func a() {
+	foo := 1
+	_ = foo + foov2.Hello
+}
+
+func b() {
+	foov2 := 2
+	_ = foov2 + foo.Hello
+}
+`,
+			wantOut: `package main
+
+import (
+	"example.org/foo/v2"
+	foov2 "example.org/foo/v2"
+)
+
+func a() {
+	foo := 1
+	_ = foo + foov2.Hello
+}
+
+func b() {
+	foov2 := 2
+	_ = foov2 + foo.Hello
+}
+`,
+			wantWarnings: []string{
+				"cannot merge the imports of example.org/foo/v2 at line 4: names foo, foov2 are shadowed by the declarations in the file",
+			},
+		},
+		{
+			name: "should report the dot imports",
+			gofile: `package main
+
+import (
+	. "example.org/foo"
+	"example.org/foo/v2"
+)
+
+var _ = Hello
+`,
+			wantOut: `package main
+
+import (
+	"example.org/foo/v2"
+	. "example.org/foo/v2"
+)
+
+var _ = Hello
+`,
+			wantWarnings: []string{
+				"cannot merge the imports of example.org/foo/v2 at line 4: dot imports are not supported",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var warnings []string
+			ctx := &transformers.Context{
+				Path: "main.go",
+				OnWarning: func(w transformers.Warning) {
+					warnings = append(warnings, w.Message)
+				},
+			}
+
+			r, w := bytes.NewBufferString(tt.gofile), &bytes.Buffer{}
+
+			ok, err := UpdateImportsFunc("example.org/foo/v2")(ctx, r, w)
+			if err != nil {
+				t.Fatalf("UpdateImportsFunc() error = %v", err)
+			}
+
+			if !ok {
+				t.Fatal("UpdateImportsFunc() ok = false, want true")
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateImportsFunc() out = %s, wantOut %s", out, tt.wantOut)
+			}
+
+			if !reflect.DeepEqual(warnings, tt.wantWarnings) {
+				t.Fatalf("UpdateImportsFunc() warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
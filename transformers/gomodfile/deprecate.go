@@ -0,0 +1,217 @@
+package gomodfile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// Retraction is a range of the module versions to retract.
+type Retraction struct {
+	modfile.VersionInterval
+
+	// Rationale is the reason of the retraction written as a comment above
+	// the retract directive. It is optional.
+	Rationale string
+}
+
+// ParseRetraction parses a single version, such as "v1.0.0", or a closed
+// interval of versions, such as "[v1.0.0, v1.2.0]", as in the retract
+// directive of a go.mod file.
+func ParseRetraction(s string) (Retraction, error) {
+	var r Retraction
+
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		low, high, ok := strings.Cut(s[1:len(s)-1], ",")
+		if !ok {
+			return r, fmt.Errorf("invalid version interval %q", s)
+		}
+		r.Low, r.High = strings.TrimSpace(low), strings.TrimSpace(high)
+	} else {
+		r.Low, r.High = s, s
+	}
+
+	for _, v := range []string{r.Low, r.High} {
+		if !semver.IsValid(v) || semver.Canonical(v) != v {
+			return r, fmt.Errorf("invalid version %q in %q", v, s)
+		}
+	}
+
+	if semver.Compare(r.Low, r.High) > 0 {
+		return r, fmt.Errorf("invalid version interval %q, %s is higher than %s", s, r.Low, r.High)
+	}
+
+	return r, nil
+}
+
+// Deprecate marks the module of a go.mod file as deprecated in favour of the
+// module with the new path by adding the "Deprecated:" comment above the module
+// directive. It also adds the retract directives for the given versions.
+//
+// The new module path must be a different major version of the same module.
+func Deprecate(
+	newModulePath string,
+	retractions ...Retraction,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		bb, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		mf, err := modfile.Parse("", bb, nil)
+		if err != nil {
+			return false, err
+		}
+
+		if mf.Module == nil {
+			return false, fmt.Errorf("module directive is missing")
+		}
+
+		pfx, _, ok := module.SplitPathVersion(newModulePath)
+		if !ok {
+			return false, fmt.Errorf("module path %s is invalid", newModulePath)
+		}
+
+		if op, _, _ := module.SplitPathVersion(mf.Module.Mod.Path); op != pfx {
+			return false, fmt.Errorf(
+				"module path %s is not a major version of %s",
+				newModulePath,
+				mf.Module.Mod.Path,
+			)
+		}
+
+		if mf.Module.Mod.Path == newModulePath {
+			return false, fmt.Errorf("module %s cannot be deprecated in favour of itself", newModulePath)
+		}
+
+		oldMsg := mf.Module.Deprecated
+		deprecated := deprecateModule(mf, newModulePath)
+
+		var added []modfile.VersionInterval
+		for _, r := range retractions {
+			if isRetracted(mf, r.VersionInterval) {
+				continue
+			}
+
+			if err := mf.AddRetract(r.VersionInterval, r.Rationale); err != nil {
+				return false, err
+			}
+			// AddRetract of x/mod v0.9.0 adds the line but not the entry that isRetracted checks.
+			mf.Retract = append(mf.Retract, &modfile.Retract{VersionInterval: r.VersionInterval})
+			added = append(added, r.VersionInterval)
+		}
+
+		if !deprecated && len(added) == 0 {
+			return false, nil
+		}
+
+		bb, err = mf.Format()
+		if err != nil {
+			return false, err
+		}
+
+		// The positions of the added lines are only known once the file is
+		// formatted.
+		nf, err := modfile.Parse("", bb, nil)
+		if err != nil {
+			return false, err
+		}
+
+		if deprecated {
+			ctx.Report(nf.Module.Syntax.Start.Line-1, 1, oldMsg, deprecationMessage(newModulePath))
+		}
+
+		for _, vi := range added {
+			for _, r := range nf.Retract {
+				if r.VersionInterval == vi {
+					ctx.Report(r.Syntax.Start.Line, r.Syntax.Start.LineRune, "", formatRetraction(vi))
+				}
+			}
+		}
+
+		if _, err := out.Write(bb); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// deprecationMessage returns the deprecation message pointing to the module
+// with the new path.
+func deprecationMessage(newModulePath string) string {
+	return fmt.Sprintf("use %s instead.", newModulePath)
+}
+
+// deprecateModule adds or replaces the deprecation comment of the module
+// directive. It returns false if the module is already deprecated with the
+// same message.
+func deprecateModule(mf *modfile.File, newModulePath string) bool {
+	msg := deprecationMessage(newModulePath)
+	if mf.Module.Deprecated == msg {
+		return false
+	}
+
+	token := "// Deprecated: " + msg
+	comments := mf.Module.Syntax.Comment()
+
+	for i, c := range comments.Before {
+		if !strings.HasPrefix(c.Token, "// Deprecated:") {
+			continue
+		}
+
+		// The deprecation message spans the whole paragraph, up to the empty
+		// comment line or the end of the comments.
+		end := i + 1
+		for end < len(comments.Before) && !isEmptyComment(comments.Before[end]) {
+			end++
+		}
+
+		comments.Before[i].Token = token
+		comments.Before = append(comments.Before[:i+1], comments.Before[end:]...)
+
+		return true
+	}
+
+	if len(comments.Before) > 0 {
+		// Separate the deprecation notice from the existing comments by an
+		// empty comment line, so that it forms its own paragraph.
+		comments.Before = append(comments.Before, modfile.Comment{Token: "//"})
+	}
+	comments.Before = append(comments.Before, modfile.Comment{Token: token})
+
+	return true
+}
+
+// isEmptyComment reports if the comment line has no text, separating the
+// paragraphs of the comment.
+func isEmptyComment(c modfile.Comment) bool {
+	return strings.TrimSpace(strings.TrimPrefix(c.Token, "//")) == ""
+}
+
+// isRetracted reports if the version interval is already retracted.
+func isRetracted(mf *modfile.File, vi modfile.VersionInterval) bool {
+	for _, r := range mf.Retract {
+		if r.VersionInterval == vi {
+			return true
+		}
+	}
+
+	return false
+}
+
+// formatRetraction formats the version interval as in the retract directive.
+func formatRetraction(vi modfile.VersionInterval) string {
+	if vi.Low == vi.High {
+		return "retract " + vi.Low
+	}
+
+	return fmt.Sprintf("retract [%s, %s]", vi.Low, vi.High)
+}
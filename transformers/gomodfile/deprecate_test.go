@@ -0,0 +1,221 @@
+package gomodfile_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	. "github.com/danilvpetrov/gobump/transformers/gomodfile"
+	"golang.org/x/mod/modfile"
+)
+
+func TestDeprecate(t *testing.T) {
+	tests := []struct {
+		name          string
+		newModulePath string
+		retractions   []Retraction
+		modfile       string
+		wantOk        bool
+		wantErr       bool
+		wantOut       string
+	}{
+		{
+			name:          "should add deprecation comment",
+			newModulePath: "example.com/foo/bar/v2",
+			modfile: `module example.com/foo/bar
+
+go 1.20
+`,
+			wantOk: true,
+			wantOut: `// Deprecated: use example.com/foo/bar/v2 instead.
+module example.com/foo/bar
+
+go 1.20
+`,
+		},
+		{
+			name:          "should keep existing comments",
+			newModulePath: "example.com/foo/bar/v3",
+			modfile: `// Package bar does things.
+module example.com/foo/bar/v2
+
+go 1.20
+`,
+			wantOk: true,
+			wantOut: `// Package bar does things.
+//
+// Deprecated: use example.com/foo/bar/v3 instead.
+module example.com/foo/bar/v2
+
+go 1.20
+`,
+		},
+		{
+			name:          "should replace existing deprecation comment",
+			newModulePath: "example.com/foo/bar/v3",
+			modfile: `// Deprecated: use example.com/foo/bar/v2 instead.
+module example.com/foo/bar
+
+go 1.20
+`,
+			wantOk: true,
+			wantOut: `// Deprecated: use example.com/foo/bar/v3 instead.
+module example.com/foo/bar
+
+go 1.20
+`,
+		},
+		{
+			name:          "should replace multi-line deprecation comment",
+			newModulePath: "example.com/foo/bar/v3",
+			modfile: `// Package bar does things.
+//
+// Deprecated: the module is no longer maintained,
+// use example.com/foo/bar/v2 instead.
+//
+// See the changelog for details.
+module example.com/foo/bar
+
+go 1.20
+`,
+			wantOk: true,
+			wantOut: `// Package bar does things.
+//
+// Deprecated: use example.com/foo/bar/v3 instead.
+//
+// See the changelog for details.
+module example.com/foo/bar
+
+go 1.20
+`,
+		},
+		{
+			name:          "should add retract directives",
+			newModulePath: "example.com/foo/bar/v2",
+			retractions: []Retraction{
+				{VersionInterval: modfile.VersionInterval{Low: "v1.0.0", High: "v1.0.0"}},
+				{
+					VersionInterval: modfile.VersionInterval{Low: "v1.1.0", High: "v1.2.0"},
+					Rationale:       "Published by mistake.",
+				},
+			},
+			modfile: `// Deprecated: use example.com/foo/bar/v2 instead.
+module example.com/foo/bar
+
+go 1.20
+
+retract v1.0.0
+`,
+			wantOk: true,
+			wantOut: `// Deprecated: use example.com/foo/bar/v2 instead.
+module example.com/foo/bar
+
+go 1.20
+
+retract (
+	v1.0.0
+	// Published by mistake.
+	[v1.1.0, v1.2.0]
+)
+`,
+		},
+		{
+			name:          "should not change already deprecated module",
+			newModulePath: "example.com/foo/bar/v2",
+			modfile: `// Deprecated: use example.com/foo/bar/v2 instead.
+module example.com/foo/bar
+
+go 1.20
+`,
+			wantOk: false,
+		},
+		{
+			name:          "should return error if module path is not a major version of the module",
+			newModulePath: "example.com/bar/foo/v2",
+			modfile: `module example.com/foo/bar
+
+go 1.20
+`,
+			wantErr: true,
+		},
+		{
+			name:          "should return error if module path is the same",
+			newModulePath: "example.com/foo/bar",
+			modfile: `module example.com/foo/bar
+
+go 1.20
+`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.modfile), &bytes.Buffer{}
+
+			ok, err := Deprecate(tt.newModulePath, tt.retractions...)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Deprecate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("Deprecate() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("Deprecate() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestDeprecate_report(t *testing.T) {
+	var got []transformers.Change
+	ctx := &transformers.Context{
+		Path: "go.mod",
+		OnChange: func(c transformers.Change) {
+			got = append(got, c)
+		},
+	}
+
+	in := bytes.NewBufferString("module example.com/foo/bar\n\ngo 1.20\n")
+	vi := modfile.VersionInterval{Low: "v1.0.0", High: "v1.1.0"}
+
+	if _, err := Deprecate("example.com/foo/bar/v2", Retraction{VersionInterval: vi})(ctx, in, &bytes.Buffer{}); err != nil {
+		t.Fatalf("Deprecate() error = %v", err)
+	}
+
+	want := []transformers.Change{
+		{Path: "go.mod", Line: 1, Column: 1, New: "use example.com/foo/bar/v2 instead."},
+		{Path: "go.mod", Line: 6, Column: 1, New: "retract [v1.0.0, v1.1.0]"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Deprecate() reported %+v, want %+v", got, want)
+	}
+}
+
+func TestParseRetraction(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    modfile.VersionInterval
+		wantErr bool
+	}{
+		{in: "v1.0.0", want: modfile.VersionInterval{Low: "v1.0.0", High: "v1.0.0"}},
+		{in: "[v1.0.0, v1.2.0]", want: modfile.VersionInterval{Low: "v1.0.0", High: "v1.2.0"}},
+		{in: "[v1.2.0, v1.0.0]", wantErr: true},
+		{in: "[v1.0.0]", wantErr: true},
+		{in: "v1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseRetraction(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRetraction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.VersionInterval != tt.want {
+				t.Fatalf("ParseRetraction() = %v, want %v", got.VersionInterval, tt.want)
+			}
+		})
+	}
+}
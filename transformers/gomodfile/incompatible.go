@@ -0,0 +1,92 @@
+package gomodfile
+
+import (
+	"io"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+)
+
+// IsIncompatible reports if the version is a "+incompatible" version of a
+// v2+ module published before the module adopted go.mod, such as
+// "v3.2.1+incompatible".
+func IsIncompatible(version string) bool {
+	return semver.Build(version) == "+incompatible"
+}
+
+// DropIncompatible removes the requirements of the "+incompatible" versions
+// of the modules from a go.mod file.
+//
+// The "+incompatible" versions are required using the module path without the
+// major version suffix. Once the module adopts go.mod at v2+, its new major
+// versions are required using the path with the suffix, so the old
+// requirement is replaced rather than updated. The requirement of the new
+// module path is expected to be added by 'go get'.
+func DropIncompatible(
+	modulePaths ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		pfxs, err := pathx.ModulePrefixes(modulePaths)
+		if err != nil {
+			return false, err
+		}
+
+		// The requirement of the unversioned path is the one being updated
+		// to, if any.
+		incompatible := map[string]bool{}
+		for i, pfx := range pfxs {
+			if pfx != modulePaths[i] {
+				incompatible[pfx] = true
+			}
+		}
+
+		if len(incompatible) == 0 {
+			return false, nil
+		}
+
+		bb, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		mf, err := modfile.Parse("", bb, nil)
+		if err != nil {
+			return false, err
+		}
+
+		var dropped []*modfile.Require
+		for _, r := range mf.Require {
+			if incompatible[r.Mod.Path] && IsIncompatible(r.Mod.Version) {
+				dropped = append(dropped, r)
+			}
+		}
+
+		if len(dropped) == 0 {
+			return false, nil
+		}
+
+		for _, r := range dropped {
+			start := r.Syntax.Start
+			ctx.Report(start.Line, start.LineRune, r.Mod.Path+" "+r.Mod.Version, "")
+
+			if err := mf.DropRequire(r.Mod.Path); err != nil {
+				return false, err
+			}
+		}
+
+		mf.Cleanup()
+
+		bb, err = mf.Format()
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := out.Write(bb); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
@@ -0,0 +1,136 @@
+package gomodfile_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	. "github.com/danilvpetrov/gobump/transformers/gomodfile"
+)
+
+func TestDropIncompatible(t *testing.T) {
+	tests := []struct {
+		name       string
+		modulePath string
+		modfile    string
+		wantOk     bool
+		wantErr    bool
+		wantOut    string
+	}{
+		{
+			name:       "should drop +incompatible requirement of the module",
+			modulePath: "example.com/foo/v4",
+			modfile: `module example.com/app
+
+go 1.20
+
+require (
+	example.com/bar v1.0.0
+	example.com/foo v3.2.1+incompatible
+)
+`,
+			wantOk: true,
+			wantOut: `module example.com/app
+
+go 1.20
+
+require example.com/bar v1.0.0
+`,
+		},
+		{
+			name:       "should drop single-line +incompatible requirement",
+			modulePath: "example.com/foo/v4",
+			modfile: `module example.com/app
+
+go 1.20
+
+require example.com/foo v3.2.1+incompatible // indirect
+`,
+			wantOk: true,
+			wantOut: `module example.com/app
+
+go 1.20
+`,
+		},
+		{
+			name:       "should not drop compatible requirement",
+			modulePath: "example.com/foo/v2",
+			modfile: `module example.com/app
+
+go 1.20
+
+require example.com/foo v1.2.0
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should not drop requirement of other modules",
+			modulePath: "example.com/foo/v4",
+			modfile: `module example.com/app
+
+go 1.20
+
+require example.com/foobar v3.0.0+incompatible
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should not drop requirement when updating to the unversioned path",
+			modulePath: "example.com/foo",
+			modfile: `module example.com/app
+
+go 1.20
+
+require example.com/foo v3.2.1+incompatible
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should return error if the module path is invalid",
+			modulePath: "example.com/foo/v1",
+			modfile:    "module example.com/app\n",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.modfile), &bytes.Buffer{}
+
+			ok, err := DropIncompatible(tt.modulePath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DropIncompatible() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("DropIncompatible() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("DropIncompatible() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestDropIncompatible_report(t *testing.T) {
+	var got []transformers.Change
+	ctx := &transformers.Context{
+		Path: "go.mod",
+		OnChange: func(c transformers.Change) {
+			got = append(got, c)
+		},
+	}
+
+	in := bytes.NewBufferString("module example.com/app\n\nrequire example.com/foo v3.2.1+incompatible\n")
+	if _, err := DropIncompatible("example.com/foo/v4")(ctx, in, &bytes.Buffer{}); err != nil {
+		t.Fatalf("DropIncompatible() error = %v", err)
+	}
+
+	want := []transformers.Change{
+		{Path: "go.mod", Line: 3, Column: 1, Old: "example.com/foo v3.2.1+incompatible"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("DropIncompatible() reported %+v, want %+v", got, want)
+	}
+}
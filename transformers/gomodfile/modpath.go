@@ -1,19 +1,27 @@
 package gomodfile
 
 import (
-	"fmt"
 	"io"
 
+	"github.com/danilvpetrov/gobump/internal/pathx"
 	"github.com/danilvpetrov/gobump/transformers"
 	"golang.org/x/mod/modfile"
-	"golang.org/x/mod/module"
 )
 
 // UpdateModulePath replaces the module path in a go.mod file.
 func UpdateModulePath(
 	modulePath string,
 ) transformers.Transformer {
-	return func(in io.Reader, out io.Writer) (ok bool, err error) {
+	return UpdateModulePathFunc(modulePath).Transformer()
+}
+
+// UpdateModulePathFunc replaces the module path in a go.mod file with the one
+// of the given module paths that is a major version of the module. The
+// replaced path is reported to the context.
+func UpdateModulePathFunc(
+	modulePaths ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
 		bb, err := io.ReadAll(in)
 		if err != nil {
 			return false, err
@@ -24,18 +32,28 @@ func UpdateModulePath(
 			return false, err
 		}
 
-		pfx, _, ok := module.SplitPathVersion(modulePath)
-		if !ok {
-			return false, fmt.Errorf("module path %s is invalid", modulePath)
+		pfxs, err := pathx.ModulePrefixes(modulePaths)
+		if err != nil {
+			return false, err
+		}
+
+		var modulePath string
+		for i, pfx := range pfxs {
+			if pathx.ModulePrefix(mf.Module.Mod.Path) == pfx {
+				modulePath = modulePaths[i]
+				break
+			}
 		}
 
-		if op, _, _ := module.SplitPathVersion(mf.Module.Mod.Path); op != pfx {
+		if modulePath == "" || mf.Module.Mod.Path == modulePath {
 			return false, nil
 		}
 
+		oldPath, start := mf.Module.Mod.Path, mf.Module.Syntax.Start
 		if err := mf.AddModuleStmt(modulePath); err != nil {
 			return false, err
 		}
+		ctx.Report(start.Line, start.LineRune+len("module "), oldPath, modulePath)
 
 		bb, err = mf.Format()
 		if err != nil {
@@ -47,6 +47,15 @@ go 1.20
 			modulePath: "example.com/bar/foo",
 			modfile: `module example.com/foo/bar
 
+go 1.20
+`,
+			wantOk: false,
+		},
+		{
+			name:       "should not update the same module path",
+			modulePath: "example.com/foo/bar/v2",
+			modfile: `module example.com/foo/bar/v2
+
 go 1.20
 `,
 			wantOk: false,
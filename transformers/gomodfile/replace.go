@@ -0,0 +1,65 @@
+package gomodfile
+
+import (
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/mod/modfile"
+)
+
+// RebaseReplacements updates the replace directives of a go.mod file copied
+// to another directory, so that the relative directory paths they refer to
+// keep pointing to the same directories. The dir is the slash-separated path
+// of the original directory relative to the new one, such as "..".
+func RebaseReplacements(dir string) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		bb, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		mf, err := modfile.Parse("", bb, nil)
+		if err != nil {
+			return false, err
+		}
+
+		var isModified bool
+		for _, r := range mf.Replace {
+			// The absolute paths and the module paths are left intact.
+			if !modfile.IsDirectoryPath(r.New.Path) || filepath.IsAbs(r.New.Path) {
+				continue
+			}
+
+			np := path.Join(dir, filepath.ToSlash(r.New.Path))
+			if np != ".." && !strings.HasPrefix(np, "../") {
+				np = "./" + np
+			}
+
+			start := r.Syntax.Start
+			ctx.Report(start.Line, start.LineRune, r.New.Path, np)
+
+			if err := mf.AddReplace(r.Old.Path, r.Old.Version, np, ""); err != nil {
+				return false, err
+			}
+			isModified = true
+		}
+
+		if !isModified {
+			return false, nil
+		}
+
+		bb, err = mf.Format()
+		if err != nil {
+			return false, err
+		}
+
+		if _, err := out.Write(bb); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
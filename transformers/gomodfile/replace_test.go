@@ -0,0 +1,75 @@
+package gomodfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/gomodfile"
+)
+
+func TestRebaseReplacements(t *testing.T) {
+	tests := []struct {
+		name    string
+		dir     string
+		modfile string
+		wantOk  bool
+		wantOut string
+	}{
+		{
+			name: "should rebase relative replacements",
+			dir:  "..",
+			modfile: `module example.com/app
+
+go 1.20
+
+replace (
+	example.com/bar => ../bar
+	example.com/baz v1.0.0 => ./internal/baz
+	example.com/qux => /src/qux
+	example.com/quux => example.com/fork/quux v1.2.0
+)
+`,
+			wantOk: true,
+			wantOut: `module example.com/app
+
+go 1.20
+
+replace (
+	example.com/bar => ../../bar
+	example.com/baz v1.0.0 => ../internal/baz
+	example.com/qux => /src/qux
+	example.com/quux => example.com/fork/quux v1.2.0
+)
+`,
+		},
+		{
+			name: "should not change module replacements",
+			dir:  "..",
+			modfile: `module example.com/app
+
+go 1.20
+
+replace example.com/quux => example.com/fork/quux v1.2.0
+`,
+			wantOk: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.modfile), &bytes.Buffer{}
+
+			ok, err := RebaseReplacements(tt.dir)(nil, r, w)
+			if err != nil {
+				t.Fatalf("RebaseReplacements() error = %v", err)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("RebaseReplacements() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("RebaseReplacements() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+// Package gotmplfile updates the module paths in the Go source templates, such
+// as the ones used by the code generators.
+package gotmplfile
+
+import (
+	"bytes"
+	"go/scanner"
+	"go/token"
+	"io"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+)
+
+// UpdateImports replaces the import paths in a Go source template, such as a
+// *.go.tmpl file, with the new import paths applicable to them.
+//
+// The template is not parsed as Go source. Instead, the quoted strings in the
+// template text holding the paths of the module are updated, which covers the
+// import declarations as well as the paths mentioned elsewhere in the code.
+// The template actions, delimited with "{{" and "}}", are left intact, and so
+// is the part of a quoted path following an action.
+func UpdateImports(newImportPaths ...string) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		pfxs, err := pathx.ModulePrefixes(newImportPaths)
+		if err != nil {
+			return false, err
+		}
+
+		src, err := io.ReadAll(in)
+		if err != nil {
+			return false, err
+		}
+
+		if !pathx.ContainsAny(src, pfxs) {
+			return false, nil
+		}
+
+		masked := maskActions(src)
+		modules := ctx.KnownModules()
+
+		fset := token.NewFileSet()
+		file := fset.AddFile("", fset.Base(), len(masked))
+
+		var s scanner.Scanner
+		s.Init(file, masked, nil, 0)
+
+		var (
+			buf  bytes.Buffer
+			last int
+		)
+		for {
+			pos, tok, lit := s.Scan()
+			if tok == token.EOF {
+				break
+			}
+			if tok != token.STRING || len(lit) < 2 {
+				continue
+			}
+
+			// The path ends where the first action starts.
+			p := lit[1 : len(lit)-1]
+			if i := strings.IndexAny(p, " \n\\"); i >= 0 {
+				p = strings.TrimSuffix(p[:i], "/")
+			}
+
+			np, ok, err := pathx.UpdateImportPathAny(newImportPaths, p, modules...)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				continue
+			}
+
+			position := fset.Position(pos)
+			start := position.Offset + 1
+
+			buf.Write(src[last:start])
+			buf.WriteString(np)
+			last = start + len(p)
+
+			ctx.Report(position.Line, position.Column, p, np)
+		}
+
+		if last == 0 {
+			return false, nil
+		}
+
+		buf.Write(src[last:])
+
+		if _, err := buf.WriteTo(out); err != nil {
+			return false, err
+		}
+
+		return true, nil
+	}
+}
+
+// maskActions returns a copy of the template with the actions replaced with
+// spaces, so that the rest of the template can be scanned as Go source. The
+// line breaks are kept, so that the positions in the copy match the
+// positions in the template.
+func maskActions(src []byte) []byte {
+	res := bytes.Clone(src)
+
+	for i := 0; i < len(res); {
+		start := bytes.Index(res[i:], []byte("{{"))
+		if start < 0 {
+			break
+		}
+		start += i
+
+		end := actionEnd(res, start+2)
+		for j := start; j < end; j++ {
+			if res[j] != '\n' {
+				res[j] = ' '
+			}
+		}
+
+		i = end
+	}
+
+	return res
+}
+
+// actionEnd returns the offset following the "}}" closing the action whose
+// content starts at the given offset, skipping the quoted strings within the
+// action. It returns the length of the source if the action is not closed.
+func actionEnd(src []byte, i int) int {
+	for i < len(src) {
+		switch c := src[i]; c {
+		case '"', '\'', '`':
+			i++
+			for i < len(src) && src[i] != c {
+				if src[i] == '\\' && c != '`' {
+					i++
+				}
+				i++
+			}
+			i++
+		case '}':
+			if i+1 < len(src) && src[i+1] == '}' {
+				return i + 2
+			}
+			i++
+		default:
+			i++
+		}
+	}
+
+	return len(src)
+}
@@ -0,0 +1,148 @@
+package gotmplfile_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/danilvpetrov/gobump/transformers"
+	. "github.com/danilvpetrov/gobump/transformers/gotmplfile"
+)
+
+func TestUpdateImports(t *testing.T) {
+	tests := []struct {
+		name          string
+		template      string
+		newImportPath string
+		wantOk        bool
+		wantErr       bool
+		wantOut       string
+	}{
+		{
+			name: "should update import paths mixed with template actions",
+			template: `package {{ .Package }}
+
+import (
+	"fmt"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+	"example.org/foo/bar/pkg"
+	{{ if .Mocks }}mocks "example.org/foo/bar/mocks"{{ end }}
+)
+
+var _ = fmt.Sprint("{{ "}}" }}")
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        true,
+			wantOut: `package {{ .Package }}
+
+import (
+	"fmt"
+{{- range .Imports }}
+	"{{ . }}"
+{{- end }}
+	"example.org/foo/bar/v2/pkg"
+	{{ if .Mocks }}mocks "example.org/foo/bar/v2/mocks"{{ end }}
+)
+
+var _ = fmt.Sprint("{{ "}}" }}")
+`,
+		},
+		{
+			name: "should update the path preceding an action",
+			template: `package foo
+
+import "example.org/foo/bar/{{ .Sub }}"
+
+const pkg = ` + "`example.org/foo/bar`" + `
+`,
+			newImportPath: "example.org/foo/bar/v3",
+			wantOk:        true,
+			wantOut: `package foo
+
+import "example.org/foo/bar/v3/{{ .Sub }}"
+
+const pkg = ` + "`example.org/foo/bar/v3`" + `
+`,
+		},
+		{
+			name: "should leave the paths in template actions and comments intact",
+			template: `package foo
+
+// Imports example.org/foo/bar.
+import (
+	{{ template "import" "example.org/foo/bar" }}
+)
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
+		},
+		{
+			name: "should leave the paths of other modules intact",
+			template: `package foo
+
+import "example.org/foo/barbaz"
+`,
+			newImportPath: "example.org/foo/bar/v2",
+			wantOk:        false,
+		},
+		{
+			name:          "should return error if the module path is invalid",
+			template:      "package foo\n",
+			newImportPath: "example.org/foo/bar/v1",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.template), &bytes.Buffer{}
+
+			ok, err := UpdateImports(tt.newImportPath)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateImports() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateImports() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateImports() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
+
+func TestUpdateImports_report(t *testing.T) {
+	var got []transformers.Change
+	ctx := &transformers.Context{
+		Path:     "main.go.tmpl",
+		OnChange: func(c transformers.Change) { got = append(got, c) },
+	}
+
+	r, w := bytes.NewBufferString(`package {{ .Package }}
+
+import {{ .Name }} "example.org/foo/bar/sub"
+`), &bytes.Buffer{}
+
+	ok, err := UpdateImports("example.org/foo/bar/v2")(ctx, r, w)
+	if err != nil {
+		t.Fatalf("UpdateImports() error = %v", err)
+	}
+
+	if !ok {
+		t.Fatal("UpdateImports() ok = false, want true")
+	}
+
+	want := []transformers.Change{{
+		Path:   "main.go.tmpl",
+		Line:   3,
+		Column: 20,
+		Old:    "example.org/foo/bar/sub",
+		New:    "example.org/foo/bar/v2/sub",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("UpdateImports() reported %+v, want %+v", got, want)
+	}
+}
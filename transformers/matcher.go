@@ -0,0 +1,103 @@
+package transformers
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Matcher reports if a transformer applies to the file with the given
+// slash-separated path.
+//
+// The head function returns up to SniffLen leading bytes of the file. It
+// should only be called by the matchers that need to sniff the file content,
+// as reading the file is much more expensive than matching its name.
+type Matcher func(path string, head func() []byte) bool
+
+// MatchBase returns a matcher matching the files with one of the given base
+// names, such as "go.mod".
+func MatchBase(names ...string) Matcher {
+	return func(p string, _ func() []byte) bool {
+		b := path.Base(p)
+		for _, n := range names {
+			if b == n {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchExt returns a matcher matching the files with one of the given
+// extensions, such as ".go".
+func MatchExt(exts ...string) Matcher {
+	return func(p string, _ func() []byte) bool {
+		e := path.Ext(p)
+		for _, x := range exts {
+			if e == x {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchGlob returns a matcher matching the files with one of the given
+// patterns as per path.Match. The patterns without slashes are matched
+// against the base name of the file, the others against its full path.
+func MatchGlob(patterns ...string) Matcher {
+	return func(p string, _ func() []byte) bool {
+		for _, pt := range patterns {
+			name := p
+			if !strings.Contains(pt, "/") {
+				name = path.Base(p)
+			}
+
+			if ok, _ := path.Match(pt, name); ok {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// MatchContent returns a matcher matching the files whose leading bytes match
+// the regular expression.
+func MatchContent(re *regexp.Regexp) Matcher {
+	return func(_ string, head func() []byte) bool {
+		return re.Match(head())
+	}
+}
+
+// MatchAll returns a matcher matching the files matched by all of the given
+// matchers. The matchers are evaluated in order, so the cheaper ones should
+// come first.
+func MatchAll(mm ...Matcher) Matcher {
+	return func(p string, head func() []byte) bool {
+		for _, m := range mm {
+			if !m(p, head) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// MatchAny returns a matcher matching the files matched by any of the given
+// matchers. The matchers are evaluated in order, so the cheaper ones should
+// come first.
+func MatchAny(mm ...Matcher) Matcher {
+	return func(p string, head func() []byte) bool {
+		for _, m := range mm {
+			if m(p, head) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
@@ -0,0 +1,40 @@
+package transformers_test
+
+import (
+	"regexp"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestMatchers(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		path    string
+		head    string
+		want    bool
+	}{
+		{name: "base name matches", matcher: MatchBase("go.mod"), path: "sub/go.mod", want: true},
+		{name: "base name does not match", matcher: MatchBase("go.mod"), path: "sub/go.sum", want: false},
+		{name: "extension matches", matcher: MatchExt(".yml", ".yaml"), path: "api/cfg.yaml", want: true},
+		{name: "extension does not match", matcher: MatchExt(".go"), path: "main.go.tmpl", want: false},
+		{name: "glob matches base name", matcher: MatchGlob("*.go.tmpl"), path: "gen/main.go.tmpl", want: true},
+		{name: "glob matches full path", matcher: MatchGlob("api/*.yaml"), path: "api/cfg.yaml", want: true},
+		{name: "glob does not match full path", matcher: MatchGlob("api/*.yaml"), path: "sub/api/cfg.yaml", want: false},
+		{name: "content matches", matcher: MatchContent(regexp.MustCompile(`(?m)^import-mapping:`)), head: "package: api\nimport-mapping:\n", want: true},
+		{name: "content does not match", matcher: MatchContent(regexp.MustCompile(`(?m)^import-mapping:`)), head: "package: api\n", want: false},
+		{name: "all matchers match", matcher: MatchAll(MatchExt(".yaml"), MatchGlob("cfg*")), path: "cfg.yaml", want: true},
+		{name: "not all matchers match", matcher: MatchAll(MatchExt(".yaml"), MatchGlob("cfg*")), path: "api.yaml", want: false},
+		{name: "any matcher matches", matcher: MatchAny(MatchExt(".yaml"), MatchExt(".json")), path: "cfg.json", want: true},
+		{name: "no matcher matches", matcher: MatchAny(MatchExt(".yaml"), MatchExt(".json")), path: "cfg.toml", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			head := func() []byte { return []byte(tt.head) }
+			if got := tt.matcher(tt.path, head); got != tt.want {
+				t.Errorf("Matcher() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
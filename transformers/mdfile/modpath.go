@@ -0,0 +1,546 @@
+package mdfile
+
+import (
+	"bufio"
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+	"github.com/danilvpetrov/gobump/transformers"
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+var (
+	headingRe = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?\s*#*\s*$`)
+	setextRe  = regexp.MustCompile(`^ {0,3}(=+|-+)[ \t]*$`)
+	fenceRe   = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	goCmdRe   = regexp.MustCompile(`\bgo\s+(?:get|install)\b`)
+	urlRe     = regexp.MustCompile(
+		`(pkg\.go\.dev/(?:badge/)?|godoc\.org/|goreportcard\.com/(?:report|badge)/)` +
+			`([A-Za-z0-9._~\-/]+)(@[A-Za-z0-9._+\-]+)?`,
+	)
+)
+
+// UpdateModulePath updates the Go module paths in a Markdown file.
+//
+// It rewrites the module paths in 'go get' and 'go install' commands,
+// pkg.go.dev, godoc.org and goreportcard.com links and badges, and
+// import declarations in fenced Go code blocks. Any other mentions of the
+// module path in the prose are left intact.
+//
+// The sections with headings listed in skipSections (case-insensitive) are
+// not modified at all, including their subsections. It allows keeping the
+// historical sections, such as changelogs, referring to the old module path.
+// Both the ATX headings, such as "## Changelog", and the single-line Setext
+// headings, underlined with "=" or "-", are recognised.
+//
+// The line endings of the file are preserved, and so is the absence of the
+// final newline.
+func UpdateModulePath(
+	modulePaths []string,
+	skipSections ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
+		if _, err := pathx.ModulePrefixes(modulePaths); err != nil {
+			return false, err
+		}
+
+		var (
+			buf        bytes.Buffer
+			isModified bool
+			skipLevel  int
+			fence      string
+			fenceLang  string
+			block      []string
+			blockEOLs  []string
+		)
+
+		lines, eols, err := readLines(in)
+		if err != nil {
+			return false, err
+		}
+
+		for i, l := range lines {
+			line, eol := i+1, eols[i]
+
+			if fence != "" {
+				if !isClosingFence(l, fence) {
+					block = append(block, l)
+					blockEOLs = append(blockEOLs, eol)
+					continue
+				}
+
+				b := codeBlock{
+					ctx:  ctx,
+					line: line - len(block),
+					lang: fenceLang,
+					skip: skipLevel > 0,
+				}
+				bl, ok, err := b.update(block, modulePaths)
+				if err != nil {
+					return false, err
+				}
+				if ok {
+					isModified = true
+				}
+
+				writeBlock(&buf, bl, blockEOLs)
+				buf.WriteString(l + eol)
+
+				fence, fenceLang, block, blockEOLs = "", "", nil, nil
+				continue
+			}
+
+			if m := fenceRe.FindStringSubmatch(l); m != nil {
+				fence, fenceLang = m[1], strings.ToLower(m[2])
+				buf.WriteString(l + eol)
+				continue
+			}
+
+			if level, text, ok := heading(l, lines[i+1:]); ok {
+				if skipLevel > 0 && level <= skipLevel {
+					skipLevel = 0
+				}
+				if skipLevel == 0 && isSkipped(text, skipSections) {
+					skipLevel = level
+				}
+			}
+
+			if skipLevel == 0 {
+				nl, ok, err := updateLine(l, modulePaths, ctx.KnownModules(), lineReporter(ctx, line))
+				if err != nil {
+					return false, err
+				}
+				if ok {
+					isModified = true
+					l = nl
+				}
+			}
+
+			buf.WriteString(l + eol)
+		}
+
+		// Flush the unterminated code block as is.
+		writeBlock(&buf, block, blockEOLs)
+
+		if isModified {
+			if _, err := buf.WriteTo(out); err != nil {
+				return false, err
+			}
+		}
+
+		return isModified, nil
+	}
+}
+
+// readLines reads the lines of the file. It returns the lines without the
+// line endings and the line endings, the last of which is empty if the file
+// does not end with a newline, so that the file is written back with the
+// original line endings.
+func readLines(in io.Reader) (lines, eols []string, _ error) {
+	r := bufio.NewReader(in)
+
+	for {
+		l, err := r.ReadString('\n')
+		if err == io.EOF && l == "" {
+			return lines, eols, nil
+		}
+		if err != nil && err != io.EOF {
+			return nil, nil, err
+		}
+
+		eol := ""
+		switch {
+		case strings.HasSuffix(l, "\r\n"):
+			l, eol = l[:len(l)-2], "\r\n"
+		case strings.HasSuffix(l, "\n"):
+			l, eol = l[:len(l)-1], "\n"
+		}
+
+		lines = append(lines, l)
+		eols = append(eols, eol)
+	}
+}
+
+// writeBlock writes the lines of a code block with the line endings they were
+// read with.
+func writeBlock(buf *bytes.Buffer, lines, eols []string) {
+	for i, l := range lines {
+		buf.WriteString(l + eols[i])
+	}
+}
+
+// heading returns the level and the text of the heading on the given line,
+// followed by the given lines. A Setext heading is recognised if the line is
+// followed by an underline, multi-line Setext headings are not supported.
+func heading(l string, next []string) (level int, text string, ok bool) {
+	if m := headingRe.FindStringSubmatch(l); m != nil {
+		return len(m[1]), m[2], true
+	}
+
+	if len(next) == 0 || strings.TrimSpace(l) == "" || fenceRe.MatchString(l) {
+		return 0, "", false
+	}
+
+	// Indented lines are code blocks.
+	if len(l)-len(strings.TrimLeft(l, " ")) > 3 || strings.HasPrefix(l, "\t") {
+		return 0, "", false
+	}
+
+	m := setextRe.FindStringSubmatch(next[0])
+	if m == nil {
+		return 0, "", false
+	}
+
+	if m[1][0] == '=' {
+		return 1, l, true
+	}
+
+	return 2, l, true
+}
+
+// reportFunc reports a path replaced at the given byte offset of a line.
+type reportFunc func(offset int, oldPath, newPath string)
+
+// lineReporter returns a function reporting the changes in the given line to
+// the context.
+func lineReporter(ctx *transformers.Context, line int) reportFunc {
+	return func(offset int, oldPath, newPath string) {
+		ctx.Report(line, offset+1, oldPath, newPath)
+	}
+}
+
+// codeBlock is a fenced code block.
+type codeBlock struct {
+	ctx  *transformers.Context
+	line int // line of the first line of the block content
+	lang string
+	skip bool
+}
+
+// update updates the lines of a fenced code block. Go code blocks have their
+// imports updated, the other blocks are treated as shell snippets that may
+// contain 'go get' and 'go install' commands.
+func (b codeBlock) update(
+	block []string,
+	modulePaths []string,
+) (_ []string, ok bool, _ error) {
+	if b.skip || len(block) == 0 {
+		return block, false, nil
+	}
+
+	if b.lang == "go" || b.lang == "golang" {
+		return b.updateGo(block, modulePaths)
+	}
+
+	var isModified bool
+	for i, l := range block {
+		nl, ok, err := updateCommands(l, modulePaths, b.ctx.KnownModules(), lineReporter(b.ctx, b.line+i))
+		if err != nil {
+			return nil, false, err
+		}
+		if ok {
+			isModified = true
+			block[i] = nl
+		}
+	}
+
+	return block, isModified, nil
+}
+
+// updateGo updates the imports in a Go code block. Snippets without a
+// package clause are temporarily wrapped into one. Only the import path
+// literals are replaced, the rest of the snippet, including its formatting,
+// is left intact. Snippets whose imports cannot be parsed are left intact.
+func (b codeBlock) updateGo(block, modulePaths []string) (_ []string, ok bool, _ error) {
+	const stub = "package stub\n\n"
+
+	src := strings.Join(block, "\n") + "\n"
+
+	// The offsets of the wrapped snippet are shifted by the stub.
+	shift := 0
+	if !strings.HasPrefix(strings.TrimSpace(src), "package ") {
+		shift = len(stub)
+	}
+
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "", stub[:shift]+src, parser.ImportsOnly)
+	if err != nil {
+		// The snippet is not a valid Go source, nothing can be done about it.
+		b.ctx.Logf("skipping Go code block at line %d: %s", b.line, err)
+		return block, false, nil
+	}
+
+	var (
+		res     strings.Builder
+		last    int
+		modules = b.ctx.KnownModules()
+	)
+	for _, i := range f.Imports {
+		p, err := strconv.Unquote(i.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		np, ok, err := pathx.UpdateImportPathAny(modulePaths, p, modules...)
+		if err != nil {
+			return nil, false, err
+		}
+		if !ok {
+			continue
+		}
+
+		pos := fset.Position(i.Path.Pos())
+		start := pos.Offset - shift
+
+		res.WriteString(src[last:start])
+		res.WriteString(strconv.Quote(np))
+		last = start + len(i.Path.Value)
+
+		// Report the changes relative to the Markdown file.
+		line := b.line + pos.Line - 1 - strings.Count(stub[:shift], "\n")
+		b.ctx.Report(line, pos.Column, p, np)
+	}
+
+	if last == 0 {
+		return block, false, nil
+	}
+
+	res.WriteString(src[last:])
+
+	return strings.Split(strings.TrimSuffix(res.String(), "\n"), "\n"), true, nil
+}
+
+// updateLine updates the module paths in the commands and links found in the
+// given Markdown line.
+func updateLine(
+	l string,
+	modulePaths []string,
+	modules []string,
+	report reportFunc,
+) (_ string, ok bool, _ error) {
+	l, cmdOK, err := updateCommands(l, modulePaths, modules, report)
+	if err != nil {
+		return "", false, err
+	}
+
+	l, urlOK, err := updateURLs(l, modulePaths, modules, report)
+	if err != nil {
+		return "", false, err
+	}
+
+	return l, cmdOK || urlOK, nil
+}
+
+// updateCommands updates the package arguments of 'go get' and 'go install'
+// commands found in the line.
+func updateCommands(
+	l string,
+	modulePaths []string,
+	modules []string,
+	report reportFunc,
+) (_ string, ok bool, _ error) {
+	var (
+		b          strings.Builder
+		isModified bool
+		last       int
+	)
+
+	for _, loc := range goCmdRe.FindAllStringIndex(l, -1) {
+		if loc[0] < last {
+			continue
+		}
+
+		b.WriteString(l[last:loc[1]])
+		last = loc[1]
+
+		for last < len(l) {
+			// Copy the whitespace preceding the argument.
+			i := last
+			for i < len(l) && (l[i] == ' ' || l[i] == '\t') {
+				i++
+			}
+			b.WriteString(l[last:i])
+			last = i
+
+			j := i
+			for j < len(l) && l[j] != ' ' && l[j] != '\t' && l[j] != '`' {
+				j++
+			}
+			arg := l[i:j]
+
+			if arg == "" || arg == "&&" || arg == "||" || arg == ";" || arg == "|" {
+				break
+			}
+
+			na, ok, err := updateCommandArg(arg, modulePaths, modules)
+			if err != nil {
+				return "", false, err
+			}
+			if ok {
+				isModified = true
+				report(i, arg, na)
+				arg = na
+			}
+
+			b.WriteString(arg)
+			last = j
+		}
+	}
+
+	if !isModified {
+		return l, false, nil
+	}
+
+	b.WriteString(l[last:])
+
+	return b.String(), true, nil
+}
+
+// updateCommandArg updates a single 'go get' or 'go install' argument, such as
+// "example.org/foo/cmd/foo@v1.2.3" or "example.org/foo/...".
+func updateCommandArg(arg string, modulePaths, modules []string) (_ string, ok bool, _ error) {
+	if strings.HasPrefix(arg, "-") {
+		return "", false, nil
+	}
+
+	p, v, _ := strings.Cut(arg, "@")
+
+	var suffix string
+	if strings.HasSuffix(p, "/...") {
+		p, suffix = strings.TrimSuffix(p, "/..."), "/..."
+	}
+
+	np, modulePath, ok, err := updatePath(modulePaths, p, modules)
+	if err != nil || !ok {
+		return "", false, err
+	}
+
+	np += suffix
+	if strings.Contains(arg, "@") {
+		np += "@" + updateVersion(v, modulePath)
+	}
+
+	return np, true, nil
+}
+
+// updateURLs updates the module paths in the pkg.go.dev, godoc.org and
+// goreportcard.com links found in the line.
+func updateURLs(
+	l string,
+	modulePaths []string,
+	modules []string,
+	report reportFunc,
+) (_ string, ok bool, _ error) {
+	var (
+		b          strings.Builder
+		isModified bool
+		last       int
+	)
+
+	for _, sm := range urlRe.FindAllStringSubmatchIndex(l, -1) {
+		m, host, p := l[sm[0]:sm[1]], l[sm[2]:sm[3]], l[sm[4]:sm[5]]
+
+		var v string
+		if sm[6] >= 0 {
+			v = l[sm[6]:sm[7]]
+		}
+
+		// Trailing punctuation most likely belongs to the prose, and the
+		// extension belongs to the badge image.
+		trimmed := strings.TrimRight(strings.TrimSuffix(p, ".svg"), "./")
+		tail := p[len(trimmed):]
+
+		np, modulePath, ok, err := updatePath(modulePaths, trimmed, modules)
+		if err != nil {
+			return "", false, err
+		}
+		if !ok {
+			continue
+		}
+
+		isModified = true
+		report(sm[4], trimmed, np)
+
+		// Drop the version if it does not belong to the new major version.
+		if v == "" || updateVersion(v[1:], modulePath) == "latest" {
+			v = ""
+		}
+
+		b.WriteString(l[last:sm[0]])
+		b.WriteString(host + np + v + tail)
+		last = sm[0] + len(m)
+	}
+
+	if !isModified {
+		return l, false, nil
+	}
+
+	b.WriteString(l[last:])
+
+	return b.String(), true, nil
+}
+
+// updatePath updates the path to the first of the new module paths it
+// belongs to, see pathx.UpdateImportPath. It also returns the module path the
+// path is updated to.
+func updatePath(
+	modulePaths []string,
+	p string,
+	modules []string,
+) (_, modulePath string, ok bool, _ error) {
+	for _, m := range modulePaths {
+		np, ok, err := pathx.UpdateImportPath(m, p, modules...)
+		if err != nil || ok {
+			return np, m, ok, err
+		}
+	}
+
+	return "", "", false, nil
+}
+
+// updateVersion returns the version unchanged if it is compatible with the
+// major version of the module path, or "latest" otherwise.
+func updateVersion(v, modulePath string) string {
+	if !semver.IsValid(v) {
+		return v
+	}
+
+	_, pv, _ := module.SplitPathVersion(modulePath)
+	if module.CheckPathMajor(v, pv) != nil {
+		return "latest"
+	}
+
+	return v
+}
+
+// isClosingFence reports if the line closes the code block opened with the
+// given fence.
+func isClosingFence(l, fence string) bool {
+	t := strings.TrimSpace(l)
+	if len(l)-len(strings.TrimLeft(l, " ")) > 3 {
+		return false
+	}
+
+	return len(t) >= len(fence) &&
+		strings.Trim(t, fence[:1]) == "" &&
+		t[0] == fence[0]
+}
+
+// isSkipped reports if the section with the given heading must be skipped.
+func isSkipped(heading string, skipSections []string) bool {
+	heading = strings.TrimSpace(heading)
+	for _, s := range skipSections {
+		if strings.EqualFold(heading, strings.TrimSpace(s)) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,253 @@
+package mdfile_test
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump/transformers/mdfile"
+)
+
+func TestUpdateModulePath(t *testing.T) {
+	tests := []struct {
+		name         string
+		modulePaths  []string
+		skipSections []string
+		mdfile       string
+		wantOut      string
+		wantOk       bool
+		wantErr      bool
+	}{
+		{
+			name:        "should update 'go get' and 'go install' commands",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "# Foo\n" +
+				"\n" +
+				"```sh\n" +
+				"go get example.org/foo/bar\n" +
+				"go install example.org/foo/bar/cmd/bar@latest\n" +
+				"go get -u example.org/foo/bar/...@v1.2.3 example.org/baz\n" +
+				"```\n" +
+				"\n" +
+				"Run `go get example.org/foo/bar@v1.0.0` to install.\n",
+			wantOut: "# Foo\n" +
+				"\n" +
+				"```sh\n" +
+				"go get example.org/foo/bar/v2\n" +
+				"go install example.org/foo/bar/v2/cmd/bar@latest\n" +
+				"go get -u example.org/foo/bar/v2/...@latest example.org/baz\n" +
+				"```\n" +
+				"\n" +
+				"Run `go get example.org/foo/bar/v2@latest` to install.\n",
+			wantOk: true,
+		},
+		{
+			name:        "should update pkg.go.dev and goreportcard.com links",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "[![Go Reference](https://pkg.go.dev/badge/example.org/foo/bar.svg)](https://pkg.go.dev/example.org/foo/bar)\n" +
+				"[![Go Report Card](https://goreportcard.com/badge/example.org/foo/bar)](https://goreportcard.com/report/example.org/foo/bar)\n" +
+				"See https://pkg.go.dev/example.org/foo/bar/sub@v1.2.3#Foo.\n",
+			wantOut: "[![Go Reference](https://pkg.go.dev/badge/example.org/foo/bar/v2.svg)](https://pkg.go.dev/example.org/foo/bar/v2)\n" +
+				"[![Go Report Card](https://goreportcard.com/badge/example.org/foo/bar/v2)](https://goreportcard.com/report/example.org/foo/bar/v2)\n" +
+				"See https://pkg.go.dev/example.org/foo/bar/v2/sub#Foo.\n",
+			wantOk: true,
+		},
+		{
+			name:        "should update imports in Go code blocks",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "```go\n" +
+				"import \"example.org/foo/bar\"\n" +
+				"\n" +
+				"func main() {\n" +
+				"\tbar.Do()\n" +
+				"}\n" +
+				"```\n",
+			wantOut: "```go\n" +
+				"import \"example.org/foo/bar/v2\"\n" +
+				"\n" +
+				"func main() {\n" +
+				"\tbar.Do()\n" +
+				"}\n" +
+				"```\n",
+			wantOk: true,
+		},
+		{
+			name:        "should leave the formatting of Go code blocks intact",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "```go\n" +
+				"import (\n" +
+				"    \"fmt\"\n" +
+				"    bar  \"example.org/foo/bar\"\n" +
+				")\n" +
+				"\n" +
+				"func main()  { bar.Do() }\n" +
+				"```\n",
+			wantOut: "```go\n" +
+				"import (\n" +
+				"    \"fmt\"\n" +
+				"    bar  \"example.org/foo/bar/v2\"\n" +
+				")\n" +
+				"\n" +
+				"func main()  { bar.Do() }\n" +
+				"```\n",
+			wantOk: true,
+		},
+		{
+			name:        "should leave invalid Go code blocks intact",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "```go\n" +
+				"bar.Do(\"example.org/foo/bar\")\n" +
+				"```\n",
+			wantOk: false,
+		},
+		{
+			name:        "should leave the prose intact",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile:      "The example.org/foo/bar module does things.\n",
+			wantOk:      false,
+		},
+		{
+			name:         "should leave skipped sections intact",
+			modulePaths:  []string{"example.org/foo/bar/v2"},
+			skipSections: []string{"changelog"},
+			mdfile: "# Foo\n" +
+				"\n" +
+				"## Changelog\n" +
+				"\n" +
+				"### v1.0.0\n" +
+				"\n" +
+				"    go get example.org/foo/bar@v1.0.0\n" +
+				"\n" +
+				"```sh\n" +
+				"go get example.org/foo/bar@v1.0.0\n" +
+				"```\n" +
+				"\n" +
+				"## Installation\n" +
+				"\n" +
+				"    go get example.org/foo/bar\n",
+			wantOut: "# Foo\n" +
+				"\n" +
+				"## Changelog\n" +
+				"\n" +
+				"### v1.0.0\n" +
+				"\n" +
+				"    go get example.org/foo/bar@v1.0.0\n" +
+				"\n" +
+				"```sh\n" +
+				"go get example.org/foo/bar@v1.0.0\n" +
+				"```\n" +
+				"\n" +
+				"## Installation\n" +
+				"\n" +
+				"    go get example.org/foo/bar/v2\n",
+			wantOk: true,
+		},
+		{
+			name:         "should leave skipped Setext sections intact",
+			modulePaths:  []string{"example.org/foo/bar/v2"},
+			skipSections: []string{"changelog"},
+			mdfile: "Foo\n" +
+				"===\n" +
+				"\n" +
+				"Changelog\n" +
+				"---------\n" +
+				"\n" +
+				"    go get example.org/foo/bar@v1.0.0\n" +
+				"\n" +
+				"Installation\n" +
+				"------------\n" +
+				"\n" +
+				"    go get example.org/foo/bar\n",
+			wantOut: "Foo\n" +
+				"===\n" +
+				"\n" +
+				"Changelog\n" +
+				"---------\n" +
+				"\n" +
+				"    go get example.org/foo/bar@v1.0.0\n" +
+				"\n" +
+				"Installation\n" +
+				"------------\n" +
+				"\n" +
+				"    go get example.org/foo/bar/v2\n",
+			wantOk: true,
+		},
+		{
+			name:        "should update module paths to an older version",
+			modulePaths: []string{"example.org/foo/bar"},
+			mdfile: "go install example.org/foo/bar/v2/cmd/bar@v2.1.0\n" +
+				"https://pkg.go.dev/example.org/foo/bar/v2\n",
+			wantOut: "go install example.org/foo/bar/cmd/bar@latest\n" +
+				"https://pkg.go.dev/example.org/foo/bar\n",
+			wantOk: true,
+		},
+		{
+			name:        "should update several module paths at once",
+			modulePaths: []string{"example.org/foo/v2", "example.org/bar/v3"},
+			mdfile: "go get example.org/foo@v1.2.0 example.org/bar/v3@v3.1.0\n" +
+				"https://pkg.go.dev/example.org/bar/v2/pkg\n",
+			wantOut: "go get example.org/foo/v2@latest example.org/bar/v3@v3.1.0\n" +
+				"https://pkg.go.dev/example.org/bar/v3/pkg\n",
+			wantOk: true,
+		},
+		{
+			name:        "should preserve CRLF line endings",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "# Foo\r\n" +
+				"\r\n" +
+				"```go\r\n" +
+				"import \"example.org/foo/bar\"\r\n" +
+				"```\r\n" +
+				"\r\n" +
+				"    go get example.org/foo/bar\r\n",
+			wantOut: "# Foo\r\n" +
+				"\r\n" +
+				"```go\r\n" +
+				"import \"example.org/foo/bar/v2\"\r\n" +
+				"```\r\n" +
+				"\r\n" +
+				"    go get example.org/foo/bar/v2\r\n",
+			wantOk: true,
+		},
+		{
+			name:        "should not add a final newline",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile: "# Foo\n" +
+				"\n" +
+				"    go get example.org/foo/bar",
+			wantOut: "# Foo\n" +
+				"\n" +
+				"    go get example.org/foo/bar/v2",
+			wantOk: true,
+		},
+		{
+			name:        "should not update non-matching module paths",
+			modulePaths: []string{"example.org/foo/bar/v2"},
+			mdfile:      "go get example.org/bar/foo\n",
+			wantOk:      false,
+		},
+		{
+			name:        "should return an error if the module path is invalid",
+			modulePaths: []string{"example.org/foo/bar/v1"},
+			mdfile:      "go get example.org/foo/bar\n",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, w := bytes.NewBufferString(tt.mdfile), &bytes.Buffer{}
+
+			ok, err := UpdateModulePath(tt.modulePaths, tt.skipSections...)(nil, r, w)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UpdateModulePath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if ok != tt.wantOk {
+				t.Fatalf("UpdateModulePath() ok = %v, wantOk %v", ok, tt.wantOk)
+			}
+
+			if out := w.String(); out != tt.wantOut {
+				t.Fatalf("UpdateModulePath() out = %s, wantOut %s", out, tt.wantOut)
+			}
+		})
+	}
+}
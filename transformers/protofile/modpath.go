@@ -7,27 +7,43 @@ import (
 	"io"
 	"strings"
 
+	"github.com/danilvpetrov/gobump/internal/pathx"
 	"github.com/danilvpetrov/gobump/transformers"
-	"github.com/danilvpetrov/gobump/transformers/internal/pathx"
 )
 
 // UpdateModulePath updates a corresponding a Go module path in *.proto  file.
 func UpdateModulePath(
 	modulePath string,
 ) transformers.Transformer {
-	return func(in io.Reader, out io.Writer) (ok bool, err error) {
+	return UpdateModulePathFunc(modulePath).Transformer()
+}
+
+// UpdateModulePathFunc updates the Go module paths in the imports and the
+// go_package options of a *.proto file, and reports them to the context.
+func UpdateModulePathFunc(
+	modulePaths ...string,
+) transformers.Func {
+	return func(ctx *transformers.Context, in io.Reader, out io.Writer) (ok bool, err error) {
 		var (
 			buf        bytes.Buffer
 			isModified bool
+			line       int
 		)
 		s := bufio.NewScanner(in)
+		modules := ctx.KnownModules()
 
 		for s.Scan() {
 			l := s.Text()
+			line++
+
+			report := func(oldPath, newPath string) {
+				// The path is the first quoted string in the line.
+				ctx.Report(line, strings.IndexByte(l, '"')+2, oldPath, newPath)
+			}
 
 			switch {
 			case strings.HasPrefix(l, "import"):
-				nl, ok, err := updateImport(l, modulePath)
+				nl, ok, err := updateImport(l, modulePaths, modules, report)
 				if err != nil {
 					return false, err
 				}
@@ -36,7 +52,7 @@ func UpdateModulePath(
 					l = nl
 				}
 			case strings.HasPrefix(l, "option go_package"):
-				nl, ok, err := updateGoPkgOption(l, modulePath)
+				nl, ok, err := updateGoPkgOption(l, modulePaths, modules, report)
 				if err != nil {
 					return false, err
 				}
@@ -61,14 +77,20 @@ func UpdateModulePath(
 }
 
 // updateImport updates and returns an import statement with the new Golang
-// module path. If the import statement is not updated, ok is returned as false.
-func updateImport(importStmt, newModulePath string) (_ string, ok bool, _ error) {
+// module paths. If the import statement is not updated, ok is returned as
+// false.
+func updateImport(
+	importStmt string,
+	newModulePaths []string,
+	modules []string,
+	report func(oldPath, newPath string),
+) (_ string, ok bool, _ error) {
 	ss := strings.Split(importStmt, `"`)
 	if len(ss) < 3 {
 		return "", false, nil
 	}
 
-	np, ok, err := pathx.UpdateImportPath(newModulePath, ss[1])
+	np, ok, err := pathx.UpdateImportPathAny(newModulePaths, ss[1], modules...)
 	if err != nil {
 		return "", false, err
 	}
@@ -77,6 +99,7 @@ func updateImport(importStmt, newModulePath string) (_ string, ok bool, _ error)
 		return "", false, nil
 	}
 
+	report(ss[1], np)
 	ss[1] = np
 
 	return strings.Join(ss, `"`), true, nil
@@ -87,7 +110,12 @@ func updateImport(importStmt, newModulePath string) (_ string, ok bool, _ error)
 //
 // See [this link](https://protobuf.dev/reference/go/go-generated/#package) for
 // reference.
-func updateGoPkgOption(option, newModulePath string) (_ string, ok bool, _ error) {
+func updateGoPkgOption(
+	option string,
+	newModulePaths []string,
+	modules []string,
+	report func(oldPath, newPath string),
+) (_ string, ok bool, _ error) {
 	ss := strings.Split(option, `"`)
 	if len(ss) < 3 {
 		return "", false, nil
@@ -100,7 +128,7 @@ func updateGoPkgOption(option, newModulePath string) (_ string, ok bool, _ error
 		path, alias = pp[0], pp[1]
 	}
 
-	np, ok, err := pathx.UpdateImportPath(newModulePath, path)
+	np, ok, err := pathx.UpdateImportPathAny(newModulePaths, path, modules...)
 	if err != nil {
 		return "", false, err
 	}
@@ -109,6 +137,7 @@ func updateGoPkgOption(option, newModulePath string) (_ string, ok bool, _ error
 		return "", false, nil
 	}
 
+	report(path, np)
 	if alias != "" {
 		ss[1] = np + ";" + alias
 	} else {
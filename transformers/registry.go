@@ -0,0 +1,140 @@
+package transformers
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+)
+
+// SniffLen is the maximum number of leading bytes of a file available to the
+// matchers sniffing the file content.
+const SniffLen = 4096
+
+// Factory creates a transformer updating the module paths to the given ones.
+// The transformer is expected to update all of them in a single pass over a
+// file.
+type Factory func(modulePaths []string) FileTransformer
+
+// FuncFactory returns a factory creating the transformers implemented as Func.
+func FuncFactory(f func(modulePaths ...string) Func) Factory {
+	return func(modulePaths []string) FileTransformer {
+		return f(modulePaths...)
+	}
+}
+
+// Registration describes a transformer registered in a registry.
+type Registration struct {
+	// Name is a unique name of the transformer.
+	Name string
+
+	// Description is a short human-readable description of the transformer.
+	Description string
+
+	// Match reports if the transformer applies to a file.
+	Match Matcher
+
+	// New creates the transformer.
+	New Factory
+}
+
+// Registry is a set of transformers along with the files they apply to.
+//
+// It is safe for concurrent use.
+type Registry struct {
+	m    sync.RWMutex
+	regs []Registration
+}
+
+// NewRegistry returns a new empty registry.
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Register adds a transformer to the registry.
+//
+// It returns an error if the registration is incomplete or a transformer with
+// the same name is already registered.
+func (r *Registry) Register(reg Registration) error {
+	if reg.Name == "" || reg.Match == nil || reg.New == nil {
+		return fmt.Errorf("transformer registration %q is incomplete", reg.Name)
+	}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	for _, e := range r.regs {
+		if e.Name == reg.Name {
+			return fmt.Errorf("transformer %q is already registered", reg.Name)
+		}
+	}
+
+	r.regs = append(r.regs, reg)
+
+	return nil
+}
+
+// Registrations returns all registered transformers sorted by name.
+func (r *Registry) Registrations() []Registration {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	regs := append([]Registration(nil), r.regs...)
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].Name < regs[j].Name
+	})
+
+	return regs
+}
+
+// Lookup returns the transformers applicable to the file with the given
+// slash-separated path in the order of their registration. The head function
+// returns the leading bytes of the file, see Matcher for details.
+func (r *Registry) Lookup(path string, head func() []byte) []Registration {
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	var res []Registration
+	for _, reg := range r.regs {
+		if reg.Match(path, head) {
+			res = append(res, reg)
+		}
+	}
+
+	return res
+}
+
+// LookupFile returns the transformers applicable to the file in fsys. The
+// file content is read only if one of the matchers sniffs it.
+func (r *Registry) LookupFile(fsys fs.FS, path string) []Registration {
+	var (
+		once sync.Once
+		head []byte
+	)
+
+	return r.Lookup(path, func() []byte {
+		once.Do(func() {
+			f, err := fsys.Open(path)
+			if err != nil {
+				return
+			}
+			defer f.Close()
+
+			head, _ = io.ReadAll(io.LimitReader(f, SniffLen))
+		})
+
+		return head
+	})
+}
+
+// Transformers returns the transformers applicable to the file in fsys
+// updating the module paths to the given ones.
+func (r *Registry) Transformers(fsys fs.FS, path string, modulePaths ...string) []FileTransformer {
+	var tt []FileTransformer
+	for _, reg := range r.LookupFile(fsys, path) {
+		tt = append(tt, reg.New(modulePaths))
+	}
+
+	return tt
+}
@@ -0,0 +1,82 @@
+package transformers_test
+
+import (
+	"io"
+	"reflect"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	. "github.com/danilvpetrov/gobump/transformers"
+)
+
+func TestRegistry(t *testing.T) {
+	nop := func([]string) FileTransformer {
+		return Transformer(func(io.Reader, io.Writer) (bool, error) { return false, nil })
+	}
+
+	r := NewRegistry()
+	for _, reg := range []Registration{
+		{Name: "yaml", Match: MatchExt(".yaml"), New: nop},
+		{Name: "config", Match: MatchContent(regexp.MustCompile(`config:`)), New: nop},
+		{Name: "go", Match: MatchExt(".go"), New: nop},
+	} {
+		if err := r.Register(reg); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	t.Run("should reject duplicate registrations", func(t *testing.T) {
+		if err := r.Register(Registration{Name: "go", Match: MatchExt(".go"), New: nop}); err == nil {
+			t.Fatal("Register() error = nil, want error")
+		}
+	})
+
+	t.Run("should reject incomplete registrations", func(t *testing.T) {
+		if err := r.Register(Registration{Name: "incomplete"}); err == nil {
+			t.Fatal("Register() error = nil, want error")
+		}
+	})
+
+	t.Run("should list registrations sorted by name", func(t *testing.T) {
+		var got []string
+		for _, reg := range r.Registrations() {
+			got = append(got, reg.Name)
+		}
+
+		if want := []string{"config", "go", "yaml"}; !reflect.DeepEqual(got, want) {
+			t.Fatalf("Registrations() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("should look up files in registration order", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"a.yaml": {Data: []byte("config: true\n")},
+			"b.yaml": {Data: []byte("other: true\n")},
+			"c.go":   {Data: []byte("package c\n")},
+			"d.txt":  {Data: []byte("config: true\n")},
+		}
+
+		want := map[string][]string{
+			"a.yaml": {"yaml", "config"},
+			"b.yaml": {"yaml"},
+			"c.go":   {"go"},
+			"d.txt":  {"config"},
+		}
+
+		for p, w := range want {
+			var got []string
+			for _, reg := range r.LookupFile(fsys, p) {
+				got = append(got, reg.Name)
+			}
+
+			if !reflect.DeepEqual(got, w) {
+				t.Errorf("LookupFile(%q) = %v, want %v", p, got, w)
+			}
+
+			if n := len(r.Transformers(fsys, p, "example.org/foo/v2")); n != len(w) {
+				t.Errorf("Transformers(%q) returned %d transformers, want %d", p, n, len(w))
+			}
+		}
+	})
+}
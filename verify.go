@@ -0,0 +1,254 @@
+package gobump
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/danilvpetrov/gobump/internal/pathx"
+)
+
+// BuildError is a diagnostic reported by the go command, such as a compiler
+// error or a vet finding.
+type BuildError struct {
+	// File is the path of the file the error is reported for, as printed by
+	// the go command.
+	File string `json:"file"`
+
+	// Line and Column is the position of the error in the file. Column is 0
+	// if the go command does not report it.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+
+	// Message is the error message.
+	Message string `json:"message"`
+
+	// Symbol is the package-qualified name of the symbol the error refers
+	// to, such as "foo.Bar" or "foo.Client.Do". It is empty if the error
+	// does not refer to a symbol of another package.
+	Symbol string `json:"symbol,omitempty"`
+
+	// Package is the import path of the package the symbol belongs to. It is
+	// empty if the package is unknown.
+	Package string `json:"package,omitempty"`
+}
+
+// SymbolKey returns the key of the symbol the error refers to, qualified by
+// the package import path if it is known.
+func (e BuildError) SymbolKey() string {
+	if e.Symbol == "" || e.Package == "" {
+		return e.Symbol
+	}
+
+	_, name, _ := strings.Cut(e.Symbol, ".")
+
+	return e.Package + "." + name
+}
+
+var (
+	diagnosticRe = regexp.MustCompile(`^(?:vet: )?(\S+\.go):(\d+)(?::(\d+))?: (.*)$`)
+
+	// symbolRes extract the symbol from the well-known error messages, in
+	// order of preference.
+	symbolRes = []*regexp.Regexp{
+		regexp.MustCompile(`type \*?(\w+\.\w+) has no field or method (\w+)`),
+		regexp.MustCompile(`undefined: (\w+\.\w+)`),
+		regexp.MustCompile(`arguments in call to (\w+\.\w+(?:\.\w+)?)`),
+		regexp.MustCompile(`in argument to (\w+\.\w+(?:\.\w+)?)`),
+		regexp.MustCompile(`does not implement (\w+\.\w+)`),
+		regexp.MustCompile(`\b([a-zA-Z_]\w*\.[A-Z]\w*)\b`),
+	}
+)
+
+// ParseBuildErrors parses the diagnostics from the output of 'go build',
+// 'go vet' or 'go test'. The duplicate diagnostics are reported once.
+func ParseBuildErrors(out string) []BuildError {
+	var (
+		res  []BuildError
+		seen = map[BuildError]bool{}
+	)
+
+	for _, l := range strings.Split(out, "\n") {
+		m := diagnosticRe.FindStringSubmatch(strings.TrimSpace(l))
+		if m == nil {
+			continue
+		}
+
+		e := BuildError{
+			File:    filepath.Clean(m[1]),
+			Message: m[4],
+			Symbol:  parseSymbol(m[4]),
+		}
+		e.Line, _ = strconv.Atoi(m[2])
+		e.Column, _ = strconv.Atoi(m[3])
+
+		if !seen[e] {
+			seen[e] = true
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
+
+// parseSymbol returns the package-qualified symbol the error message refers
+// to.
+func parseSymbol(msg string) string {
+	for _, re := range symbolRes {
+		if m := re.FindStringSubmatch(msg); m != nil {
+			return strings.Join(m[1:], ".")
+		}
+	}
+
+	return ""
+}
+
+// ResolveBuildErrors resolves the packages of the symbols the errors refer to
+// using the imports of the files the errors are reported for. The file paths
+// are relative to dir. The symbols that do not refer to an imported package
+// are dropped.
+func ResolveBuildErrors(dir string, ee []BuildError) {
+	imports := map[string]map[string]string{}
+
+	for i, e := range ee {
+		if e.Symbol == "" {
+			continue
+		}
+
+		names, ok := imports[e.File]
+		if !ok {
+			names = fileImports(filepath.Join(dir, e.File))
+			imports[e.File] = names
+		}
+
+		pkg, _, _ := strings.Cut(e.Symbol, ".")
+		if p, ok := names[pkg]; ok {
+			ee[i].Package = p
+		} else {
+			ee[i].Symbol = ""
+		}
+	}
+}
+
+// fileImports returns the import paths of the file keyed by the package names
+// they are referred to by.
+func fileImports(file string) map[string]string {
+	names := map[string]string{}
+
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.ImportsOnly)
+	if err != nil {
+		return names
+	}
+
+	for _, i := range f.Imports {
+		p, err := strconv.Unquote(i.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := pathx.AssumedPackageName(p)
+		if i.Name != nil {
+			name = i.Name.Name
+		}
+
+		names[name] = p
+	}
+
+	return names
+}
+
+// dedupBuildErrors returns the errors without the duplicates, such as the
+// ones reported by both 'go build' and 'go test'.
+func dedupBuildErrors(ee []BuildError) []BuildError {
+	var (
+		res  []BuildError
+		seen = map[BuildError]bool{}
+	)
+	for _, e := range ee {
+		if !seen[e] {
+			seen[e] = true
+			res = append(res, e)
+		}
+	}
+
+	return res
+}
+
+// GroupBuildErrors groups the errors by the given key. The errors with an
+// empty key are omitted. Within a group the errors are sorted by position.
+func GroupBuildErrors(ee []BuildError, key func(BuildError) string) map[string][]BuildError {
+	res := map[string][]BuildError{}
+
+	for _, e := range ee {
+		if k := key(e); k != "" {
+			res[k] = append(res[k], e)
+		}
+	}
+
+	for _, g := range res {
+		sort.SliceStable(g, func(i, j int) bool {
+			if g[i].File != g[j].File {
+				return g[i].File < g[j].File
+			}
+			if g[i].Line != g[j].Line {
+				return g[i].Line < g[j].Line
+			}
+			return g[i].Column < g[j].Column
+		})
+	}
+
+	return res
+}
+
+// VerifyReport is a report of building the updated modules.
+type VerifyReport struct {
+	// Errors is a list of the diagnostics reported by the go command.
+	Errors []BuildError `json:"errors"`
+
+	// ByFile is the diagnostics grouped by the file.
+	ByFile map[string][]BuildError `json:"by_file"`
+
+	// BySymbol is the diagnostics grouped by the package-qualified symbol
+	// they refer to.
+	BySymbol map[string][]BuildError `json:"by_symbol"`
+
+	// Failed is a list of the commands that exited with a non-zero status.
+	Failed []CommandFailure `json:"failed,omitempty"`
+}
+
+// CommandFailure is a go command that failed when building the updated
+// modules.
+type CommandFailure struct {
+	// Dir is the slash-separated directory of the module the command is run
+	// in, relative to the module directory.
+	Dir string `json:"dir"`
+
+	// Args is the command line.
+	Args []string `json:"args"`
+
+	// ExitCode is the exit code of the command.
+	ExitCode int `json:"exit_code"`
+
+	// Output is the output of the command if no diagnostics are parsed from
+	// it.
+	Output string `json:"output,omitempty"`
+}
+
+// NewVerifyReport returns a report of the given diagnostics.
+func NewVerifyReport(ee []BuildError) *VerifyReport {
+	if ee == nil {
+		ee = []BuildError{}
+	}
+
+	return &VerifyReport{
+		Errors: ee,
+		ByFile: GroupBuildErrors(ee, func(e BuildError) string {
+			return e.File
+		}),
+		BySymbol: GroupBuildErrors(ee, BuildError.SymbolKey),
+	}
+}
@@ -0,0 +1,89 @@
+package gobump_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/danilvpetrov/gobump"
+)
+
+func TestParseBuildErrors(t *testing.T) {
+	out := "# example.org/app/pkg\n" +
+		"pkg/a.go:5:10: undefined: foo.Bar\n" +
+		"pkg/a.go:7:2: c.Do undefined (type *foo.Client has no field or method Do)\n" +
+		"vet: pkg/b.go:3:1: not enough arguments in call to foo.New\n" +
+		"pkg/a.go:5:10: undefined: foo.Bar\n" +
+		"pkg/c.go:12: declared and not used: x\n"
+
+	got := ParseBuildErrors(out)
+	want := []BuildError{
+		{File: filepath.Clean("pkg/a.go"), Line: 5, Column: 10, Message: "undefined: foo.Bar", Symbol: "foo.Bar"},
+		{File: filepath.Clean("pkg/a.go"), Line: 7, Column: 2, Message: "c.Do undefined (type *foo.Client has no field or method Do)", Symbol: "foo.Client.Do"},
+		{File: filepath.Clean("pkg/b.go"), Line: 3, Column: 1, Message: "not enough arguments in call to foo.New", Symbol: "foo.New"},
+		{File: filepath.Clean("pkg/c.go"), Line: 12, Message: "declared and not used: x"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ParseBuildErrors() = %+v, want %+v", got, want)
+	}
+}
+
+func TestResolveBuildErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	src := "package pkg\n" +
+		"\n" +
+		"import (\n" +
+		"\t\"example.org/foo/v2\"\n" +
+		"\tq \"example.org/bar\"\n" +
+		")\n"
+	if err := os.WriteFile(filepath.Join(dir, "a.go"), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ee := []BuildError{
+		{File: "a.go", Line: 5, Symbol: "foo.Bar"},
+		{File: "a.go", Line: 6, Symbol: "q.Baz"},
+		{File: "a.go", Line: 7, Symbol: "x.Qux"},
+	}
+	ResolveBuildErrors(dir, ee)
+
+	want := []BuildError{
+		{File: "a.go", Line: 5, Symbol: "foo.Bar", Package: "example.org/foo/v2"},
+		{File: "a.go", Line: 6, Symbol: "q.Baz", Package: "example.org/bar"},
+		{File: "a.go", Line: 7},
+	}
+	if !reflect.DeepEqual(ee, want) {
+		t.Fatalf("ResolveBuildErrors() = %+v, want %+v", ee, want)
+	}
+}
+
+func TestNewVerifyReport(t *testing.T) {
+	ee := []BuildError{
+		{File: "b.go", Line: 2, Symbol: "foo.Bar", Package: "example.org/foo/v2"},
+		{File: "a.go", Line: 9, Symbol: "foo.Bar", Package: "example.org/foo/v2"},
+		{File: "a.go", Line: 1},
+	}
+
+	r := NewVerifyReport(ee)
+
+	if got := len(r.ByFile["a.go"]); got != 2 {
+		t.Fatalf("len(ByFile[a.go]) = %d, want 2", got)
+	}
+
+	if r.ByFile["a.go"][0].Line != 1 {
+		t.Fatalf("ByFile[a.go] is not sorted by position: %+v", r.ByFile["a.go"])
+	}
+
+	got := r.BySymbol["example.org/foo/v2.Bar"]
+	want := []BuildError{ee[1], ee[0]}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("BySymbol = %+v, want %+v", r.BySymbol, want)
+	}
+
+	if len(r.BySymbol) != 1 {
+		t.Fatalf("len(BySymbol) = %d, want 1", len(r.BySymbol))
+	}
+}
@@ -1,8 +1,11 @@
 package gobump
 
 import (
+	"context"
 	"io/fs"
+	"runtime"
 	"strings"
+	"sync"
 )
 
 // WalkDir walks a given implementation of fs.FS and runs f() on
@@ -10,7 +13,10 @@ import (
 //
 // This function ignores files and directories as per go command's convention.
 // See https://pkg.go.dev/cmd/go for more details.
+//
+// The walk stops with the context's error as soon as the context is canceled.
 func WalkDir(
+	ctx context.Context,
 	fsys fs.FS,
 	f func(file string) error,
 ) error {
@@ -22,6 +28,10 @@ func WalkDir(
 				return err
 			}
 
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
 			// Ignore the root directory.
 			if path == "." {
 				return nil
@@ -45,6 +55,170 @@ func WalkDir(
 	)
 }
 
+// WalkDirParallel walks a given implementation of fs.FS like WalkDir does, but
+// runs f() concurrently on up to the given number of workers. If workers is
+// not positive, runtime.GOMAXPROCS(0) workers are used.
+//
+// The results of f() are passed to collect() in the walk order from a single
+// goroutine, so that the callers can produce deterministic output regardless
+// of the order the files are processed in.
+//
+// The first error returned by f() or collect() cancels the context passed to
+// f() and stops the walk. That error is returned.
+func WalkDirParallel[T any](
+	ctx context.Context,
+	fsys fs.FS,
+	workers int,
+	f func(ctx context.Context, file string) (T, error),
+	collect func(file string, res T) error,
+) error {
+	return runParallel(
+		ctx,
+		workers,
+		func(ctx context.Context, send func(file string) error) error {
+			return WalkDir(ctx, fsys, send)
+		},
+		f,
+		collect,
+	)
+}
+
+// forEachParallel is like WalkDirParallel, but it runs f() on the given files
+// instead of walking a file system.
+func forEachParallel[T any](
+	ctx context.Context,
+	files []string,
+	workers int,
+	f func(ctx context.Context, file string) (T, error),
+	collect func(file string, res T) error,
+) error {
+	return runParallel(
+		ctx,
+		workers,
+		func(ctx context.Context, send func(file string) error) error {
+			for _, file := range files {
+				if err := send(file); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		f,
+		collect,
+	)
+}
+
+// runParallel runs f() concurrently on the files passed to send() by feed()
+// and passes the results to collect() in the order the files are sent.
+func runParallel[T any](
+	ctx context.Context,
+	workers int,
+	feed func(ctx context.Context, send func(file string) error) error,
+	f func(ctx context.Context, file string) (T, error),
+	collect func(file string, res T) error,
+) error {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type job struct {
+		seq  int
+		file string
+	}
+
+	type result struct {
+		job
+		res T
+		err error
+	}
+
+	var (
+		jobs    = make(chan job)
+		results = make(chan result)
+		walkErr error
+		wg      sync.WaitGroup
+	)
+
+	go func() {
+		defer close(jobs)
+
+		var seq int
+		walkErr = feed(ctx, func(file string) error {
+			select {
+			case jobs <- job{seq, file}:
+				seq++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+
+			for j := range jobs {
+				res, err := f(ctx, j.file)
+				results <- result{j, res, err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var (
+		firstErr error
+		next     int
+		pending  = map[int]result{}
+	)
+
+	for r := range results {
+		if firstErr != nil {
+			// Drain the results of the jobs in progress.
+			continue
+		}
+
+		if r.err != nil {
+			firstErr = r.err
+			cancel()
+			continue
+		}
+
+		pending[r.seq] = r
+		for {
+			p, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if err := collect(p.file, p.res); err != nil {
+				firstErr = err
+				cancel()
+				break
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return firstErr
+	}
+
+	// The walk goroutine has finished by now, as the jobs channel is closed
+	// before all workers exit.
+	return walkErr
+}
+
 func shouldIgnoreDir(name string) bool {
 	switch {
 	case name == "testdata":
@@ -1,10 +1,14 @@
 package gobump_test
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
+	"reflect"
 	"testing"
+	"testing/fstest"
 
 	. "github.com/danilvpetrov/gobump"
 )
@@ -66,9 +70,106 @@ func TestWalkDir(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if err := WalkDir(tt.fs, tt.f); (err != nil) != tt.wantErr {
+			if err := WalkDir(context.Background(), tt.fs, tt.f); (err != nil) != tt.wantErr {
 				t.Errorf("WalkDir() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
 }
+
+func TestWalkDir_Canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WalkDir(ctx, os.DirFS("internal/testdata/walkdir/dira"), func(file string) error {
+		t.Error("WalkDir() should not have called f()")
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("WalkDir() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestWalkDirParallel(t *testing.T) {
+	fsys := fstest.MapFS{}
+	for i := 0; i < 100; i++ {
+		fsys[fmt.Sprintf("dir%d/file%d.go", i%7, i)] = &fstest.MapFile{}
+	}
+
+	var want []string
+	if err := WalkDir(context.Background(), fsys, func(file string) error {
+		want = append(want, file)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("should collect results in walk order", func(t *testing.T) {
+		var got []string
+		err := WalkDirParallel(
+			context.Background(),
+			fsys,
+			8,
+			func(_ context.Context, file string) (string, error) {
+				return file, nil
+			},
+			func(file string, res string) error {
+				if file != res {
+					t.Errorf("WalkDirParallel() collected %q for file %q", res, file)
+				}
+				got = append(got, res)
+				return nil
+			},
+		)
+		if err != nil {
+			t.Fatalf("WalkDirParallel() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("WalkDirParallel() collected %v, want %v", got, want)
+		}
+	})
+
+	t.Run("should return the error returned by f()", func(t *testing.T) {
+		err := WalkDirParallel(
+			context.Background(),
+			fsys,
+			4,
+			func(ctx context.Context, file string) (struct{}, error) {
+				if file == want[10] {
+					return struct{}{}, errors.New("<error>")
+				}
+				return struct{}{}, ctx.Err()
+			},
+			func(string, struct{}) error { return nil },
+		)
+		if err == nil || err.Error() != "<error>" {
+			t.Fatalf("WalkDirParallel() error = %v, want <error>", err)
+		}
+	})
+
+	t.Run("should return the error returned by collect()", func(t *testing.T) {
+		var n int
+		err := WalkDirParallel(
+			context.Background(),
+			fsys,
+			0,
+			func(context.Context, string) (struct{}, error) {
+				return struct{}{}, nil
+			},
+			func(string, struct{}) error {
+				if n++; n == 5 {
+					return errors.New("<error>")
+				}
+				return nil
+			},
+		)
+		if err == nil || err.Error() != "<error>" {
+			t.Fatalf("WalkDirParallel() error = %v, want <error>", err)
+		}
+
+		if n != 5 {
+			t.Fatalf("WalkDirParallel() called collect() %d times after the error", n-5)
+		}
+	})
+}